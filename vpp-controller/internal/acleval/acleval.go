@@ -0,0 +1,186 @@
+// Package acleval evaluates ACL rules the way the VPP ACL plugin does: a rule
+// with protocol 0 matches any packet of its address family, port ranges hold
+// the ICMP type and code for ICMP rules and TCP flags are only compared for
+// TCP.
+package acleval
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+const (
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoICMPv6 = 58
+)
+
+// rule is a domain rule with parsed prefixes
+type rule struct {
+	domain.ACLRule
+	src, dst netip.Prefix
+}
+
+func compile(r domain.ACLRule) (rule, error) {
+	src, err := toPrefix(r.Src)
+	if err != nil {
+		return rule{}, fmt.Errorf("source: %w", err)
+	}
+	dst, err := toPrefix(r.Dst)
+	if err != nil {
+		return rule{}, fmt.Errorf("destination: %w", err)
+	}
+	if src.Addr().Is6() != dst.Addr().Is6() {
+		return rule{}, fmt.Errorf("source and destination address families differ")
+	}
+	return rule{ACLRule: r, src: src, dst: dst}, nil
+}
+
+func compileAll(rules []domain.ACLRule) ([]rule, error) {
+	out := make([]rule, len(rules))
+	for i, r := range rules {
+		c, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		out[i] = c
+	}
+	return out, nil
+}
+
+func toPrefix(p domain.IPWithPrefix) (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(p.Address)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid address %q", p.Address)
+	}
+	prefix, err := addr.Unmap().Prefix(int(p.Prefix))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid prefix %s/%d", p.Address, p.Prefix)
+	}
+	return prefix, nil
+}
+
+// hasPorts reports whether the port ranges of the rule are compared
+func (r rule) hasPorts() bool {
+	switch r.Proto {
+	case protoTCP, protoUDP, protoICMP, protoICMPv6:
+		return true
+	}
+	return false
+}
+
+func (r rule) isV6() bool {
+	return r.src.Addr().Is6()
+}
+
+// covers reports whether every packet matching b also matches a
+func covers(a, b rule) bool {
+	if a.isV6() != b.isV6() {
+		return false
+	}
+	if !prefixCovers(a.src, b.src) || !prefixCovers(a.dst, b.dst) {
+		return false
+	}
+	if a.Proto == 0 {
+		return true
+	}
+	if a.Proto != b.Proto {
+		return false
+	}
+	if a.hasPorts() {
+		if !rangeCovers(a.SrcPortLow, a.SrcPortHigh, b.SrcPortLow, b.SrcPortHigh) ||
+			!rangeCovers(a.DstPortLow, a.DstPortHigh, b.DstPortLow, b.DstPortHigh) {
+			return false
+		}
+	}
+	if a.Proto == protoTCP {
+		// b must test every flag a tests, with the same value
+		if a.TCPFlagsMask&^b.TCPFlagsMask != 0 {
+			return false
+		}
+		if a.TCPFlagsValue&a.TCPFlagsMask != b.TCPFlagsValue&a.TCPFlagsMask {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps reports whether some packet matches both a and b
+func overlaps(a, b rule) bool {
+	if a.isV6() != b.isV6() {
+		return false
+	}
+	if !a.src.Overlaps(b.src) || !a.dst.Overlaps(b.dst) {
+		return false
+	}
+	if a.Proto == 0 || b.Proto == 0 {
+		return true
+	}
+	if a.Proto != b.Proto {
+		return false
+	}
+	if a.hasPorts() {
+		if !rangeOverlaps(a.SrcPortLow, a.SrcPortHigh, b.SrcPortLow, b.SrcPortHigh) ||
+			!rangeOverlaps(a.DstPortLow, a.DstPortHigh, b.DstPortLow, b.DstPortHigh) {
+			return false
+		}
+	}
+	if a.Proto == protoTCP {
+		common := a.TCPFlagsMask & b.TCPFlagsMask
+		if a.TCPFlagsValue&common != b.TCPFlagsValue&common {
+			return false
+		}
+	}
+	return true
+}
+
+func prefixCovers(a, b netip.Prefix) bool {
+	return a.Bits() <= b.Bits() && a.Contains(b.Addr())
+}
+
+func rangeCovers(aLow, aHigh, bLow, bHigh uint16) bool {
+	return aLow <= bLow && bHigh <= aHigh
+}
+
+func rangeOverlaps(aLow, aHigh, bLow, bHigh uint16) bool {
+	return aLow <= bHigh && bLow <= aHigh
+}
+
+// isDeny separates the permit and deny verdicts, reflect permits
+func isDeny(action domain.ACLAction) bool {
+	return action == domain.ACLDeny
+}
+
+// matches reports whether the packet matches the rule
+func (r rule) matches(pkt domain.ACLPacket) bool {
+	src, ok := netip.AddrFromSlice(pkt.Src)
+	if !ok {
+		return false
+	}
+	dst, ok := netip.AddrFromSlice(pkt.Dst)
+	if !ok {
+		return false
+	}
+	if !r.src.Contains(src.Unmap()) || !r.dst.Contains(dst.Unmap()) {
+		return false
+	}
+	if r.Proto == 0 {
+		return true
+	}
+	if r.Proto != pkt.Proto {
+		return false
+	}
+	if r.hasPorts() {
+		if !rangeCovers(r.SrcPortLow, r.SrcPortHigh, pkt.SrcPort, pkt.SrcPort) ||
+			!rangeCovers(r.DstPortLow, r.DstPortHigh, pkt.DstPort, pkt.DstPort) {
+			return false
+		}
+	}
+	if r.Proto == protoTCP && pkt.TCPFlags&r.TCPFlagsMask != r.TCPFlagsValue&r.TCPFlagsMask {
+		return false
+	}
+	return true
+}
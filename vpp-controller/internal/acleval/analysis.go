@@ -0,0 +1,74 @@
+package acleval
+
+import (
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// Analyze compares every rule with the rules before and after it. A rule
+// covered by an earlier rule never matches: it is shadowed when the earlier
+// rule has the opposite verdict and redundant when both permit or both deny,
+// permit and reflect count as the same verdict. A rule covered by a later
+// rule with the same action is redundant too when no rule in between
+// overlaps it with another action; a later reflect rule would create
+// sessions the permit did not. Rules with a permit and a deny
+// verdict that partly overlap are reported as conflicts, the earlier one
+// decides the common packets. An earlier rule fully inside a later one with
+// the opposite verdict is an exception and is not reported.
+func Analyze(rules []domain.ACLRule) ([]domain.ACLFinding, error) {
+	compiled, err := compileAll(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []domain.ACLFinding
+	dead := make([]bool, len(compiled))
+	for j, later := range compiled {
+		for i := 0; i < j; i++ {
+			earlier := compiled[i]
+			if dead[i] || !covers(earlier, later) {
+				continue
+			}
+			kind := domain.ACLFindingRedundant
+			if isDeny(earlier.Action) != isDeny(later.Action) {
+				kind = domain.ACLFindingShadowed
+			}
+			findings = append(findings, domain.ACLFinding{Kind: kind, Rule: j, Related: i})
+			dead[j] = true
+			break
+		}
+		if dead[j] {
+			continue
+		}
+
+		if k, ok := redundantByLater(compiled, j); ok {
+			findings = append(findings, domain.ACLFinding{Kind: domain.ACLFindingRedundant, Rule: j, Related: k})
+		}
+		for i := 0; i < j; i++ {
+			earlier := compiled[i]
+			if dead[i] || isDeny(earlier.Action) == isDeny(later.Action) {
+				continue
+			}
+			if overlaps(earlier, later) && !covers(later, earlier) {
+				findings = append(findings, domain.ACLFinding{Kind: domain.ACLFindingConflict, Rule: j, Related: i})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// redundantByLater finds a later rule with the same action covering rule j
+// that would make the same decision if rule j was removed
+func redundantByLater(rules []rule, j int) (int, bool) {
+	for k := j + 1; k < len(rules); k++ {
+		if rules[k].Action != rules[j].Action {
+			if overlaps(rules[j], rules[k]) {
+				return 0, false
+			}
+			continue
+		}
+		if covers(rules[k], rules[j]) {
+			return k, true
+		}
+	}
+	return 0, false
+}
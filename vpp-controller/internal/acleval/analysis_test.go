@@ -0,0 +1,63 @@
+package acleval
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+func TestAnalyze(t *testing.T) {
+	anyV4 := domain.IPWithPrefix{Address: "0.0.0.0"}
+	net10 := domain.IPWithPrefix{Address: "10.0.0.0", Prefix: 8}
+	host := domain.IPWithPrefix{Address: "10.0.0.1", Prefix: 32}
+	rule := func(action domain.ACLAction, src domain.IPWithPrefix) domain.ACLRule {
+		return domain.ACLRule{Action: action, Src: src, Dst: anyV4, SrcPortHigh: 65535, DstPortHigh: 65535}
+	}
+
+	tests := []struct {
+		name  string
+		rules []domain.ACLRule
+		want  []domain.ACLFinding
+	}{
+		{
+			name:  "deny after permit is shadowed",
+			rules: []domain.ACLRule{rule(domain.ACLPermit, net10), rule(domain.ACLDeny, host)},
+			want:  []domain.ACLFinding{{Kind: domain.ACLFindingShadowed, Rule: 1, Related: 0}},
+		},
+		{
+			name:  "permit after reflect is redundant",
+			rules: []domain.ACLRule{rule(domain.ACLPermitReflect, net10), rule(domain.ACLPermit, host)},
+			want:  []domain.ACLFinding{{Kind: domain.ACLFindingRedundant, Rule: 1, Related: 0}},
+		},
+		{
+			name:  "reflect after permit is redundant",
+			rules: []domain.ACLRule{rule(domain.ACLPermit, net10), rule(domain.ACLPermitReflect, host)},
+			want:  []domain.ACLFinding{{Kind: domain.ACLFindingRedundant, Rule: 1, Related: 0}},
+		},
+		{
+			name:  "permit before broader permit is redundant",
+			rules: []domain.ACLRule{rule(domain.ACLPermit, host), rule(domain.ACLPermit, net10)},
+			want:  []domain.ACLFinding{{Kind: domain.ACLFindingRedundant, Rule: 0, Related: 1}},
+		},
+		{
+			name:  "permit before broader reflect is kept",
+			rules: []domain.ACLRule{rule(domain.ACLPermit, host), rule(domain.ACLPermitReflect, net10)},
+		},
+		{
+			name:  "exception is not reported",
+			rules: []domain.ACLRule{rule(domain.ACLDeny, host), rule(domain.ACLPermit, net10)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Analyze(tt.rules)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package acleval
+
+import (
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// Simulate evaluates the ACLs in order, the first matching rule decides. A
+// packet no rule matches is denied, without ACLs it is permitted. Sessions
+// of reflect rules are not taken into account.
+func Simulate(acls []domain.ACLInfo, pkt domain.ACLPacket) (domain.ACLSimulation, error) {
+	if pkt.Src.To4() == nil != (pkt.Dst.To4() == nil) {
+		return domain.ACLSimulation{}, fmt.Errorf("source and destination address families differ")
+	}
+
+	result := domain.ACLSimulation{Action: domain.ACLPermit}
+	if len(acls) == 0 {
+		return result, nil
+	}
+	result.Action = domain.ACLDeny
+	for _, acl := range acls {
+		result.Evaluated = append(result.Evaluated, acl)
+		rules, err := compileAll(acl.Rules)
+		if err != nil {
+			return domain.ACLSimulation{}, fmt.Errorf("acl %d: %w", acl.ID, err)
+		}
+		for i, r := range rules {
+			if r.matches(pkt) {
+				result.Matched = true
+				result.ACLID, result.RuleIndex = acl.ID, i
+				result.Action = r.Action
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
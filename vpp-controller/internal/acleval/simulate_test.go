@@ -0,0 +1,140 @@
+package acleval
+
+import (
+	"net"
+	"testing"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+func TestSimulate(t *testing.T) {
+	anyV4 := domain.IPWithPrefix{Address: "0.0.0.0"}
+	net10 := domain.IPWithPrefix{Address: "10.0.0.0", Prefix: 8}
+	web := domain.ACLRule{Action: domain.ACLPermit, Proto: 6, Src: net10, Dst: anyV4, SrcPortHigh: 65535, DstPortLow: 80, DstPortHigh: 80}
+	syn := domain.ACLRule{Action: domain.ACLDeny, Proto: 6, Src: anyV4, Dst: anyV4, SrcPortHigh: 65535, DstPortHigh: 65535, TCPFlagsMask: 0x12, TCPFlagsValue: 0x02}
+	echo := domain.ACLRule{Action: domain.ACLPermit, Proto: 1, Src: anyV4, Dst: anyV4, SrcPortLow: 8, SrcPortHigh: 8, DstPortHigh: 255}
+	denyAll := domain.ACLRule{Action: domain.ACLDeny, Src: anyV4, Dst: anyV4, SrcPortHigh: 65535, DstPortHigh: 65535}
+
+	first := domain.ACLInfo{ID: 1, Rules: []domain.ACLRule{syn, echo}}
+	second := domain.ACLInfo{ID: 2, Rules: []domain.ACLRule{web, denyAll}}
+	tcp := func(src string, dport uint16, flags uint8) domain.ACLPacket {
+		return domain.ACLPacket{Src: net.ParseIP(src), Dst: net.ParseIP("192.168.0.1"), Proto: 6, SrcPort: 40000, DstPort: dport, TCPFlags: flags}
+	}
+	icmp := func(icmpType, code uint16) domain.ACLPacket {
+		return domain.ACLPacket{Src: net.ParseIP("10.0.0.1"), Dst: net.ParseIP("192.168.0.1"), Proto: 1, SrcPort: icmpType, DstPort: code}
+	}
+
+	tests := []struct {
+		name        string
+		acls        []domain.ACLInfo
+		pkt         domain.ACLPacket
+		wantMatched bool
+		wantACL     domain.AclID
+		wantRule    int
+		wantAction  domain.ACLAction
+		wantEvals   int
+	}{
+		{
+			name:       "no acls permit",
+			pkt:        tcp("10.0.0.1", 80, 0x10),
+			wantAction: domain.ACLPermit,
+		},
+		{
+			name:       "no match denies",
+			acls:       []domain.ACLInfo{{ID: 3, Rules: []domain.ACLRule{web}}},
+			pkt:        tcp("172.16.0.1", 80, 0x10),
+			wantAction: domain.ACLDeny,
+			wantEvals:  1,
+		},
+		{
+			name:        "first match wins across acls",
+			acls:        []domain.ACLInfo{first, second},
+			pkt:         tcp("10.0.0.1", 80, 0x10),
+			wantMatched: true,
+			wantACL:     2,
+			wantRule:    0,
+			wantAction:  domain.ACLPermit,
+			wantEvals:   2,
+		},
+		{
+			name:        "tcp flags mask",
+			acls:        []domain.ACLInfo{first, second},
+			pkt:         tcp("10.0.0.1", 80, 0x02|0x08),
+			wantMatched: true,
+			wantACL:     1,
+			wantRule:    0,
+			wantAction:  domain.ACLDeny,
+			wantEvals:   1,
+		},
+		{
+			name:        "tcp flags outside the mask",
+			acls:        []domain.ACLInfo{first, second},
+			pkt:         tcp("10.0.0.1", 80, 0x12),
+			wantMatched: true,
+			wantACL:     2,
+			wantRule:    0,
+			wantAction:  domain.ACLPermit,
+			wantEvals:   2,
+		},
+		{
+			name:        "icmp type in the source port",
+			acls:        []domain.ACLInfo{first, second},
+			pkt:         icmp(8, 0),
+			wantMatched: true,
+			wantACL:     1,
+			wantRule:    1,
+			wantAction:  domain.ACLPermit,
+			wantEvals:   1,
+		},
+		{
+			name:        "icmp type not matched",
+			acls:        []domain.ACLInfo{first, second},
+			pkt:         icmp(0, 0),
+			wantMatched: true,
+			wantACL:     2,
+			wantRule:    1,
+			wantAction:  domain.ACLDeny,
+			wantEvals:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Simulate(tt.acls, tt.pkt)
+			if err != nil {
+				t.Fatalf("Simulate() error = %v", err)
+			}
+			if got.Matched != tt.wantMatched || got.Action != tt.wantAction || len(got.Evaluated) != tt.wantEvals {
+				t.Fatalf("Simulate() = %+v, want matched %v action %v after %d acls", got, tt.wantMatched, tt.wantAction, tt.wantEvals)
+			}
+			if tt.wantMatched && (got.ACLID != tt.wantACL || got.RuleIndex != tt.wantRule) {
+				t.Errorf("Simulate() matched acl %d rule %d, want acl %d rule %d", got.ACLID, got.RuleIndex, tt.wantACL, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestSimulateICMPCode(t *testing.T) {
+	unreachable := domain.ACLRule{
+		Action: domain.ACLPermit, Proto: 1,
+		Src: domain.IPWithPrefix{Address: "0.0.0.0"}, Dst: domain.IPWithPrefix{Address: "0.0.0.0"},
+		SrcPortLow: 3, SrcPortHigh: 3, DstPortLow: 4, DstPortHigh: 4,
+	}
+	acls := []domain.ACLInfo{{ID: 1, Rules: []domain.ACLRule{unreachable}}}
+	for code, want := range map[uint16]domain.ACLAction{4: domain.ACLPermit, 1: domain.ACLDeny} {
+		pkt := domain.ACLPacket{Src: net.ParseIP("10.0.0.1"), Dst: net.ParseIP("10.0.0.2"), Proto: 1, SrcPort: 3, DstPort: code}
+		got, err := Simulate(acls, pkt)
+		if err != nil {
+			t.Fatalf("Simulate() error = %v", err)
+		}
+		if got.Action != want {
+			t.Errorf("Simulate() code %d = %v, want %v", code, got.Action, want)
+		}
+	}
+}
+
+func TestSimulateMixedFamilies(t *testing.T) {
+	pkt := domain.ACLPacket{Src: net.ParseIP("10.0.0.1"), Dst: net.ParseIP("2001:db8::1"), Proto: 6}
+	if _, err := Simulate(nil, pkt); err == nil {
+		t.Error("Simulate() error = nil, want error")
+	}
+}
@@ -0,0 +1,288 @@
+// Package aclimport translates Linux firewall rulesets into ACL rules, one
+// ACL per chain. Rules using matches VPP ACLs cannot express are left out
+// whole and reported, as dropping a single match would widen the rule.
+package aclimport
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+const (
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoICMPv6 = 58
+
+	maxPort     = 65535
+	maxICMPType = 255
+
+	// maxExpansion caps the rules a single firewall rule may expand into
+	// through port or address sets
+	maxExpansion = 1024
+
+	// maxACLNameLen is the size of the ACL tag in VPP
+	maxACLNameLen = 64
+)
+
+// Result holds the chains that produced rules and the rules left out
+type Result struct {
+	Chains []domain.ACLChain
+	Issues []domain.ACLImportIssue
+}
+
+// SkipsDeny reports whether a DROP or REJECT rule was left out
+func (r Result) SkipsDeny() bool {
+	for _, issue := range r.Issues {
+		if issue.Deny {
+			return true
+		}
+	}
+	return false
+}
+
+type portRange struct {
+	low, high uint16
+}
+
+var anyPort = portRange{0, maxPort}
+
+// match is a firewall rule before expansion, empty slices match anything
+type match struct {
+	v4, v6     bool
+	protos     []uint8
+	src, dst   []netip.Prefix
+	sport      []portRange
+	dport      []portRange
+	icmpType   []portRange
+	icmpCode   []portRange
+	flagsMask  uint8
+	flagsValue uint8
+	action     domain.ACLAction
+	hasAction  bool
+}
+
+func newMatch(v4, v6 bool) *match {
+	return &match{v4: v4, v6: v6}
+}
+
+// restrictFamily narrows the families the rule applies to
+func (m *match) restrictFamily(v6 bool) error {
+	if v6 {
+		m.v4 = false
+	} else {
+		m.v6 = false
+	}
+	if !m.v4 && !m.v6 {
+		return fmt.Errorf("rule mixes IPv4 and IPv6 matches")
+	}
+	return nil
+}
+
+func (m *match) addPrefixes(dst bool, prefixes []netip.Prefix) error {
+	for _, prefix := range prefixes {
+		if err := m.restrictFamily(prefix.Addr().Is6()); err != nil {
+			return err
+		}
+	}
+	if dst {
+		m.dst = append(m.dst, prefixes...)
+	} else {
+		m.src = append(m.src, prefixes...)
+	}
+	return nil
+}
+
+func (m *match) setProto(proto uint8) error {
+	switch proto {
+	case protoICMP:
+		if err := m.restrictFamily(false); err != nil {
+			return err
+		}
+	case protoICMPv6:
+		if err := m.restrictFamily(true); err != nil {
+			return err
+		}
+	}
+	m.protos = []uint8{proto}
+	return nil
+}
+
+// policyRule is the catch-all rule appended for the policy of a base chain
+func policyRule(action domain.ACLAction, v4, v6 bool) *match {
+	m := newMatch(v4, v6)
+	m.action, m.hasAction = action, true
+	return m
+}
+
+// rules expands the match into ACL rules, every combination of the
+// alternatives of each field becomes one rule
+func (m *match) rules() ([]domain.ACLRule, error) {
+	if !m.hasAction {
+		return nil, fmt.Errorf("rule has no accept, drop or reject verdict")
+	}
+	protos := m.protos
+	if len(protos) == 0 {
+		if len(m.sport) > 0 || len(m.dport) > 0 {
+			protos = []uint8{protoTCP, protoUDP}
+		} else {
+			protos = []uint8{0}
+		}
+	}
+	var families []bool
+	if m.v4 {
+		families = append(families, false)
+	}
+	if m.v6 {
+		families = append(families, true)
+	}
+
+	var rules []domain.ACLRule
+	for _, proto := range protos {
+		ported := proto == protoTCP || proto == protoUDP
+		icmp := proto == protoICMP || proto == protoICMPv6
+		if (len(m.sport) > 0 || len(m.dport) > 0) && !ported {
+			return nil, fmt.Errorf("port match requires tcp or udp")
+		}
+		if (len(m.icmpType) > 0 || len(m.icmpCode) > 0) && !icmp {
+			return nil, fmt.Errorf("icmp type match requires icmp or icmpv6")
+		}
+		if m.flagsMask != 0 && proto != protoTCP {
+			return nil, fmt.Errorf("tcp flags match requires tcp")
+		}
+		srcPorts, dstPorts := orAny(m.sport, anyPort), orAny(m.dport, anyPort)
+		if icmp {
+			anyICMP := portRange{0, maxICMPType}
+			srcPorts, dstPorts = orAny(m.icmpType, anyICMP), orAny(m.icmpCode, anyICMP)
+		}
+		for _, v6 := range families {
+			if proto == protoICMP && v6 || proto == protoICMPv6 && !v6 {
+				continue
+			}
+			for _, src := range prefixesOrAny(m.src, v6) {
+				for _, dst := range prefixesOrAny(m.dst, v6) {
+					for _, sport := range srcPorts {
+						for _, dport := range dstPorts {
+							if len(rules) == maxExpansion {
+								return nil, fmt.Errorf("rule expands to more than %d ACL rules", maxExpansion)
+							}
+							rules = append(rules, domain.ACLRule{
+								Action:        m.action,
+								Proto:         proto,
+								Src:           toIPWithPrefix(src),
+								Dst:           toIPWithPrefix(dst),
+								SrcPortLow:    sport.low,
+								SrcPortHigh:   sport.high,
+								DstPortLow:    dport.low,
+								DstPortHigh:   dport.high,
+								TCPFlagsMask:  m.flagsMask,
+								TCPFlagsValue: m.flagsValue,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+	return rules, nil
+}
+
+func orAny(ranges []portRange, any portRange) []portRange {
+	if len(ranges) == 0 {
+		return []portRange{any}
+	}
+	return ranges
+}
+
+func prefixesOrAny(prefixes []netip.Prefix, v6 bool) []netip.Prefix {
+	var out []netip.Prefix
+	for _, prefix := range prefixes {
+		if prefix.Addr().Is6() == v6 {
+			out = append(out, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		if v6 {
+			return []netip.Prefix{netip.PrefixFrom(netip.IPv6Unspecified(), 0)}
+		}
+		return []netip.Prefix{netip.PrefixFrom(netip.IPv4Unspecified(), 0)}
+	}
+	return out
+}
+
+func toIPWithPrefix(prefix netip.Prefix) domain.IPWithPrefix {
+	return domain.IPWithPrefix{Address: prefix.Addr().String(), Prefix: uint8(prefix.Bits())}
+}
+
+// parsePrefix accepts an address or a prefix, the host bits are cleared
+func parsePrefix(s string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		addr, addrErr := netip.ParseAddr(s)
+		if addrErr != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid address %q", s)
+		}
+		prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+	}
+	return prefix.Masked(), nil
+}
+
+// builder collects the chains of a ruleset in order of appearance
+type builder struct {
+	result Result
+	chains map[string]int
+}
+
+func newBuilder() *builder {
+	return &builder{chains: make(map[string]int)}
+}
+
+// chain registers a chain so that chains keep the order they are declared in
+func (b *builder) chain(name string) *domain.ACLChain {
+	i, ok := b.chains[name]
+	if !ok {
+		i = len(b.result.Chains)
+		b.chains[name] = i
+		b.result.Chains = append(b.result.Chains, domain.ACLChain{Name: aclName(name)})
+	}
+	return &b.result.Chains[i]
+}
+
+// add appends the rules of m to the chain, deny is the verdict of the
+// firewall rule and is reported if the rule has to be left out
+func (b *builder) add(chain, text string, deny bool, m *match, err error) {
+	if err == nil {
+		var rules []domain.ACLRule
+		if rules, err = m.rules(); err == nil {
+			c := b.chain(chain)
+			c.Rules = append(c.Rules, rules...)
+			return
+		}
+	}
+	b.skip(chain, text, deny, err.Error())
+}
+
+func (b *builder) skip(chain, text string, deny bool, reason string) {
+	b.result.Issues = append(b.result.Issues, domain.ACLImportIssue{Chain: chain, Rule: text, Reason: reason, Deny: deny})
+}
+
+// finish drops chains without rules
+func (b *builder) finish() Result {
+	chains := b.result.Chains[:0]
+	for _, chain := range b.result.Chains {
+		if len(chain.Rules) > 0 {
+			chains = append(chains, chain)
+		}
+	}
+	b.result.Chains = chains
+	return b.result
+}
+
+func aclName(chain string) string {
+	if len(chain) > maxACLNameLen {
+		return chain[:maxACLNameLen]
+	}
+	return chain
+}
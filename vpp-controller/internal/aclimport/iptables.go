@@ -0,0 +1,399 @@
+package aclimport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+var iptablesProtos = map[string]uint8{
+	"all":       0,
+	"icmp":      protoICMP,
+	"tcp":       protoTCP,
+	"udp":       protoUDP,
+	"ipv6-icmp": protoICMPv6,
+	"icmpv6":    protoICMPv6,
+	"gre":       47,
+	"esp":       50,
+	"ah":        51,
+	"sctp":      132,
+}
+
+var iptablesTCPFlags = map[string]uint8{
+	"FIN":  0x01,
+	"SYN":  0x02,
+	"RST":  0x04,
+	"PSH":  0x08,
+	"ACK":  0x10,
+	"URG":  0x20,
+	"ALL":  0x3f,
+	"NONE": 0x00,
+}
+
+var icmp6TypeNames = map[string]uint16{
+	"echo-reply":              0,
+	"destination-unreachable": 1,
+	"packet-too-big":          2,
+	"time-exceeded":           3,
+	"parameter-problem":       4,
+	"echo-request":            128,
+	"neighbour-solicitation":  135,
+	"neighbor-solicitation":   135,
+	"neighbour-advertisement": 136,
+	"neighbor-advertisement":  136,
+	"router-solicitation":     133,
+	"router-advertisement":    134,
+	"nd-router-solicit":       133,
+	"nd-router-advert":        134,
+	"nd-neighbor-solicit":     135,
+	"nd-neighbor-advert":      136,
+}
+
+var icmp4TypeNames = map[string]uint16{
+	"echo-reply":              0,
+	"destination-unreachable": 3,
+	"source-quench":           4,
+	"redirect":                5,
+	"echo-request":            8,
+	"router-advertisement":    9,
+	"router-solicitation":     10,
+	"time-exceeded":           11,
+	"parameter-problem":       12,
+	"timestamp-request":       13,
+	"timestamp-reply":         14,
+}
+
+// ParseIPTablesSave reads the filter table of iptables-save or
+// ip6tables-save output. The address family comes from the generator comment
+// and falls back to the addresses used by the rules. Built-in chains get a
+// final rule implementing their policy.
+func ParseIPTablesSave(r io.Reader) (Result, error) {
+	var lines []string
+	v6 := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// iptables-save -c puts packet and byte counters before rules
+		if strings.HasPrefix(line, "[") {
+			if _, rule, ok := strings.Cut(line, "] "); ok {
+				line = rule
+			}
+		}
+		if strings.HasPrefix(line, "#") && strings.Contains(line, "ip6tables") {
+			v6 = true
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return Result{}, err
+	}
+	if !v6 {
+		v6 = usesIPv6(lines)
+	}
+
+	b := newBuilder()
+	table := ""
+	policies := make(map[string]domain.ACLAction)
+	var chainOrder []string
+	for n, line := range lines {
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "*"):
+			table = line[1:]
+		case line == "COMMIT":
+			table = ""
+		case table != "filter":
+			if strings.HasPrefix(line, "-A ") {
+				args, err := splitArgs(line)
+				if err != nil {
+					args = strings.Fields(line)
+				}
+				b.skip(table, line, iptablesDenies(args), fmt.Sprintf("table %s is not supported, only filter", table))
+			}
+		case strings.HasPrefix(line, ":"):
+			fields := strings.Fields(line[1:])
+			if len(fields) < 2 {
+				return Result{}, fmt.Errorf("line %d: invalid chain declaration %q", n+1, line)
+			}
+			b.chain(fields[0])
+			chainOrder = append(chainOrder, fields[0])
+			switch fields[1] {
+			case "ACCEPT":
+				policies[fields[0]] = domain.ACLPermit
+			case "DROP":
+				policies[fields[0]] = domain.ACLDeny
+			}
+		case strings.HasPrefix(line, "-A "):
+			args, err := splitArgs(line)
+			if err != nil {
+				return Result{}, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			if len(args) < 2 {
+				return Result{}, fmt.Errorf("line %d: rule without chain", n+1)
+			}
+			m, err := parseIPTablesRule(args[2:], v6)
+			b.add(args[1], line, iptablesDenies(args), m, err)
+		default:
+			return Result{}, fmt.Errorf("line %d: unexpected %q", n+1, line)
+		}
+	}
+
+	for _, chain := range chainOrder {
+		if action, ok := policies[chain]; ok {
+			b.add(chain, "", false, policyRule(action, !v6, v6), nil)
+		}
+	}
+	return b.finish(), nil
+}
+
+// iptablesDenies reports whether the rule jumps to DROP or REJECT
+func iptablesDenies(args []string) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if (args[i] == "-j" || args[i] == "--jump") && (args[i+1] == "DROP" || args[i+1] == "REJECT") {
+			return true
+		}
+	}
+	return false
+}
+
+func usesIPv6(lines []string) bool {
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "-A ") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if prefix, err := parsePrefix(field); err == nil && prefix.Addr().Is6() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitArgs splits a rule line on spaces, honouring the double quotes
+// iptables-save puts around comments
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inQuotes, inArg := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && inQuotes && i+1 < len(line):
+			i++
+			current.WriteByte(line[i])
+		case c == '"':
+			inQuotes, inArg = !inQuotes, true
+		case c == ' ' && !inQuotes:
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func parseIPTablesRule(args []string, v6 bool) (*match, error) {
+	m := newMatch(!v6, v6)
+	for i := 0; i < len(args); i++ {
+		opt := args[i]
+		value := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("option %s without value", opt)
+			}
+			i++
+			return args[i], nil
+		}
+		if opt == "!" {
+			if i+1 < len(args) {
+				return nil, fmt.Errorf("negated match %s", args[i+1])
+			}
+			return nil, fmt.Errorf("negated match")
+		}
+
+		var v string
+		var err error
+		switch opt {
+		case "-s", "--source", "-d", "--destination", "-p", "--protocol",
+			"--sport", "--source-port", "--dport", "--destination-port",
+			"--sports", "--source-ports", "--dports", "--destination-ports",
+			"--icmp-type", "--icmpv6-type", "-m", "--match", "-j", "--jump",
+			"--comment", "--reject-with":
+			if v, err = value(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch opt {
+		case "-s", "--source", "-d", "--destination":
+			prefix, err := parsePrefix(v)
+			if err != nil {
+				return nil, err
+			}
+			if err := m.addPrefixes(opt == "-d" || opt == "--destination", []netip.Prefix{prefix}); err != nil {
+				return nil, err
+			}
+		case "-p", "--protocol":
+			proto, ok := iptablesProtos[strings.ToLower(v)]
+			if !ok {
+				n, err := strconv.ParseUint(v, 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("unknown protocol %q", v)
+				}
+				proto = uint8(n)
+			}
+			if proto == 0 {
+				continue
+			}
+			if err := m.setProto(proto); err != nil {
+				return nil, err
+			}
+		case "-m", "--match":
+			switch v {
+			case "tcp", "udp", "icmp", "icmp6", "icmpv6", "multiport", "comment":
+			case "conntrack", "state":
+				return nil, fmt.Errorf("connection tracking match, use the reflect action for stateful rules")
+			default:
+				return nil, fmt.Errorf("match module %s", v)
+			}
+		case "--sport", "--source-port", "--sports", "--source-ports":
+			if m.sport, err = parseIPTablesPorts(v); err != nil {
+				return nil, err
+			}
+		case "--dport", "--destination-port", "--dports", "--destination-ports":
+			if m.dport, err = parseIPTablesPorts(v); err != nil {
+				return nil, err
+			}
+		case "--tcp-flags":
+			if i+2 >= len(args) {
+				return nil, fmt.Errorf("option %s needs a mask and a value", opt)
+			}
+			mask, err := parseIPTablesFlags(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseIPTablesFlags(args[i+2])
+			if err != nil {
+				return nil, err
+			}
+			i += 2
+			m.flagsMask, m.flagsValue = mask, value
+		case "--syn":
+			m.flagsMask, m.flagsValue = 0x17, 0x02
+		case "--icmp-type", "--icmpv6-type":
+			if m.icmpType, m.icmpCode, err = parseICMPType(v, opt == "--icmpv6-type"); err != nil {
+				return nil, err
+			}
+		case "--comment", "--reject-with":
+		case "-j", "--jump":
+			switch v {
+			case "ACCEPT":
+				m.action = domain.ACLPermit
+			case "DROP", "REJECT":
+				m.action = domain.ACLDeny
+			default:
+				return nil, fmt.Errorf("target %s is not supported, only ACCEPT, DROP and REJECT", v)
+			}
+			m.hasAction = true
+		case "-i", "--in-interface", "-o", "--out-interface":
+			return nil, fmt.Errorf("interface match %s, apply the ACL to the interface instead", opt)
+		case "-g", "--goto":
+			return nil, fmt.Errorf("goto target")
+		default:
+			return nil, fmt.Errorf("option %s", opt)
+		}
+	}
+	return m, nil
+}
+
+// parseIPTablesPorts reads N, N:M, N: and :M port specs separated by commas
+func parseIPTablesPorts(s string) ([]portRange, error) {
+	var ranges []portRange
+	for _, part := range strings.Split(s, ",") {
+		lowStr, highStr, isRange := strings.Cut(part, ":")
+		r := portRange{0, maxPort}
+		var err error
+		if lowStr != "" {
+			if r.low, err = parsePort(lowStr, maxPort); err != nil {
+				return nil, err
+			}
+		}
+		if !isRange {
+			r.high = r.low
+		} else if highStr != "" {
+			if r.high, err = parsePort(highStr, maxPort); err != nil {
+				return nil, err
+			}
+		}
+		if r.high < r.low {
+			return nil, fmt.Errorf("reversed port range %q", part)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func parsePort(s string, max uint16) (uint16, error) {
+	n, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || n > uint64(max) {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return uint16(n), nil
+}
+
+func parseIPTablesFlags(s string) (uint8, error) {
+	var flags uint8
+	for _, name := range strings.Split(s, ",") {
+		flag, ok := iptablesTCPFlags[strings.ToUpper(name)]
+		if !ok {
+			return 0, fmt.Errorf("unknown tcp flag %q", name)
+		}
+		flags |= flag
+	}
+	return flags, nil
+}
+
+// parseICMPType reads TYPE, TYPE/CODE or a type name
+func parseICMPType(s string, v6 bool) ([]portRange, []portRange, error) {
+	names := icmp4TypeNames
+	if v6 {
+		names = icmp6TypeNames
+	}
+	if s == "any" {
+		return nil, nil, nil
+	}
+	if t, ok := names[s]; ok {
+		return []portRange{{t, t}}, nil, nil
+	}
+	typeStr, codeStr, hasCode := strings.Cut(s, "/")
+	t, err := parsePort(typeStr, maxICMPType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown icmp type %q", s)
+	}
+	types := []portRange{{t, t}}
+	if !hasCode {
+		return types, nil, nil
+	}
+	c, err := parsePort(codeStr, maxICMPType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown icmp code %q", s)
+	}
+	return types, []portRange{{c, c}}, nil
+}
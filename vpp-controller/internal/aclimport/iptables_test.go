@@ -0,0 +1,146 @@
+package aclimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/acltext"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// chainRules renders the imported chains in the text syntax
+func chainRules(chains []domain.ACLChain) map[string][]string {
+	out := make(map[string][]string, len(chains))
+	for _, chain := range chains {
+		for _, rule := range chain.Rules {
+			out[chain.Name] = append(out[chain.Name], acltext.Format(rule))
+		}
+	}
+	return out
+}
+
+func TestParseIPTablesSave(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      map[string][]string
+		wantSkips int
+		wantDeny  bool
+	}{
+		{
+			name: "iptables-save",
+			input: `# Generated by iptables-save v1.8.7
+*filter
+:INPUT DROP [0:0]
+:FORWARD ACCEPT [0:0]
+-A INPUT -s 10.0.0.0/8 -p tcp -m tcp --dport 22 -j ACCEPT
+-A INPUT -p icmp -m icmp --icmp-type 8 -j ACCEPT
+-A FORWARD -d 192.168.1.1/32 -p udp -m udp --sport 1024:2048 -j REJECT --reject-with icmp-port-unreachable
+COMMIT
+`,
+			want: map[string][]string{
+				"INPUT": {
+					"permit tcp 10.0.0.0/8 any eq 22",
+					"permit icmp any any type 8",
+					"deny ip any any",
+				},
+				"FORWARD": {
+					"deny udp any range 1024 2048 host 192.168.1.1",
+					"permit ip any any",
+				},
+			},
+		},
+		{
+			name: "counters",
+			input: `*filter
+:INPUT ACCEPT [10:600]
+[5:300] -A INPUT -p tcp -m tcp --dport 80 -m comment --comment "web server" -j DROP
+COMMIT
+`,
+			want: map[string][]string{
+				"INPUT": {"deny tcp any any eq 80", "permit ip any any"},
+			},
+		},
+		{
+			name: "multiport",
+			input: `*filter
+:INPUT ACCEPT [0:0]
+-A INPUT -p tcp -m multiport --dports 80,443,8000:8080 -j ACCEPT
+COMMIT
+`,
+			want: map[string][]string{
+				"INPUT": {
+					"permit tcp any any eq 80",
+					"permit tcp any any eq 443",
+					"permit tcp any any range 8000 8080",
+					"permit ip any any",
+				},
+			},
+		},
+		{
+			name: "ip6tables policy",
+			input: `# Generated by ip6tables-save v1.8.7
+*filter
+:INPUT DROP [0:0]
+:OUTPUT ACCEPT [0:0]
+:CUSTOM - [0:0]
+-A INPUT -p ipv6-icmp -j ACCEPT
+-A CUSTOM -s 2001:db8::/32 -j ACCEPT
+COMMIT
+`,
+			want: map[string][]string{
+				"INPUT":  {"permit icmpv6 any6 any6", "deny ip any6 any6"},
+				"OUTPUT": {"permit ip any6 any6"},
+				"CUSTOM": {"permit ip 2001:db8::/32 any"},
+			},
+		},
+		{
+			name: "skipped deny",
+			input: `*nat
+:PREROUTING ACCEPT [0:0]
+-A PREROUTING -p tcp --dport 80 -j DNAT --to-destination 10.0.0.2
+COMMIT
+*filter
+:INPUT ACCEPT [0:0]
+-A INPUT -i eth1 -j DROP
+-A INPUT -m conntrack --ctstate ESTABLISHED -j ACCEPT
+COMMIT
+`,
+			want: map[string][]string{
+				"INPUT": {"permit ip any any"},
+			},
+			wantSkips: 3,
+			wantDeny:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseIPTablesSave(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseIPTablesSave() error = %v", err)
+			}
+			if got := chainRules(result.Chains); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIPTablesSave() rules = %v, want %v", got, tt.want)
+			}
+			if len(result.Issues) != tt.wantSkips {
+				t.Errorf("ParseIPTablesSave() issues = %+v, want %d", result.Issues, tt.wantSkips)
+			}
+			if result.SkipsDeny() != tt.wantDeny {
+				t.Errorf("SkipsDeny() = %v, want %v", result.SkipsDeny(), tt.wantDeny)
+			}
+		})
+	}
+}
+
+func TestParseIPTablesSaveInvalid(t *testing.T) {
+	for _, input := range []string{
+		"*filter\n:INPUT\nCOMMIT\n",
+		"*filter\n-A INPUT -m comment --comment \"open\n",
+		"*filter\nbogus\n",
+	} {
+		if _, err := ParseIPTablesSave(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseIPTablesSave(%q) error = nil, want error", input)
+		}
+	}
+}
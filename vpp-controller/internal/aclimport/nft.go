@@ -0,0 +1,497 @@
+package aclimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+var nftProtos = map[string]uint8{
+	"icmp":      protoICMP,
+	"tcp":       protoTCP,
+	"udp":       protoUDP,
+	"ipv6-icmp": protoICMPv6,
+	"icmpv6":    protoICMPv6,
+	"gre":       47,
+	"esp":       50,
+	"ah":        51,
+	"sctp":      132,
+}
+
+var nftTCPFlags = map[string]uint8{
+	"fin": 0x01,
+	"syn": 0x02,
+	"rst": 0x04,
+	"psh": 0x08,
+	"ack": 0x10,
+	"urg": 0x20,
+	"ecn": 0x40,
+	"cwr": 0x80,
+}
+
+type nftRuleset struct {
+	Nftables []map[string]json.RawMessage `json:"nftables"`
+}
+
+type nftChain struct {
+	Family string `json:"family"`
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Hook   string `json:"hook"`
+	Policy string `json:"policy"`
+}
+
+type nftRule struct {
+	Family string            `json:"family"`
+	Table  string            `json:"table"`
+	Chain  string            `json:"chain"`
+	Handle uint64            `json:"handle"`
+	Expr   []json.RawMessage `json:"expr"`
+}
+
+type nftMatch struct {
+	Op    string          `json:"op"`
+	Left  json.RawMessage `json:"left"`
+	Right json.RawMessage `json:"right"`
+}
+
+type nftPayload struct {
+	Protocol string `json:"protocol"`
+	Field    string `json:"field"`
+}
+
+type nftPrefix struct {
+	Addr string `json:"addr"`
+	Len  int    `json:"len"`
+}
+
+// ParseNFTJSON reads the output of nft -j list ruleset. Chains of ip, ip6
+// and inet tables become ACLs named family-table-chain; base chains with an
+// accept or drop policy get a final rule implementing it. Base chains of the
+// nat and route types are left out with their rules.
+func ParseNFTJSON(r io.Reader) (Result, error) {
+	var ruleset nftRuleset
+	if err := json.NewDecoder(r).Decode(&ruleset); err != nil {
+		return Result{}, fmt.Errorf("invalid nft json: %w", err)
+	}
+
+	b := newBuilder()
+	var policies []nftChain
+	unsupported := make(map[string]string)
+	for _, object := range ruleset.Nftables {
+		if raw, ok := object["chain"]; ok {
+			var chain nftChain
+			if err := json.Unmarshal(raw, &chain); err != nil {
+				return Result{}, fmt.Errorf("invalid nft chain: %w", err)
+			}
+			if _, _, ok := nftFamilies(chain.Family); !ok {
+				continue
+			}
+			name := nftChainName(chain.Family, chain.Table, chain.Name)
+			if chain.Type != "" && chain.Type != "filter" {
+				unsupported[name] = fmt.Sprintf("chain type %s is not supported, only filter", chain.Type)
+				continue
+			}
+			b.chain(name)
+			if chain.Policy != "" {
+				policies = append(policies, chain)
+			}
+		}
+		if raw, ok := object["rule"]; ok {
+			var rule nftRule
+			if err := json.Unmarshal(raw, &rule); err != nil {
+				return Result{}, fmt.Errorf("invalid nft rule: %w", err)
+			}
+			name := nftChainName(rule.Family, rule.Table, rule.Chain)
+			text := fmt.Sprintf("handle %d", rule.Handle)
+			deny := nftDenies(rule.Expr)
+			v4, v6, ok := nftFamilies(rule.Family)
+			if !ok {
+				b.skip(name, text, deny, fmt.Sprintf("family %s is not supported", rule.Family))
+				continue
+			}
+			if reason, ok := unsupported[name]; ok {
+				b.skip(name, text, deny, reason)
+				continue
+			}
+			m, err := parseNFTRule(rule.Expr, v4, v6)
+			b.add(name, text, deny, m, err)
+		}
+	}
+
+	for _, chain := range policies {
+		v4, v6, _ := nftFamilies(chain.Family)
+		name := nftChainName(chain.Family, chain.Table, chain.Name)
+		switch chain.Policy {
+		case "accept":
+			b.add(name, "", false, policyRule(domain.ACLPermit, v4, v6), nil)
+		case "drop":
+			b.add(name, "", false, policyRule(domain.ACLDeny, v4, v6), nil)
+		}
+	}
+	return b.finish(), nil
+}
+
+// nftDenies reports whether the rule has a drop or reject verdict
+func nftDenies(exprs []json.RawMessage) bool {
+	for _, raw := range exprs {
+		var expr map[string]json.RawMessage
+		if json.Unmarshal(raw, &expr) != nil {
+			continue
+		}
+		if _, ok := expr["drop"]; ok {
+			return true
+		}
+		if _, ok := expr["reject"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func nftFamilies(family string) (v4, v6, ok bool) {
+	switch family {
+	case "ip":
+		return true, false, true
+	case "ip6":
+		return false, true, true
+	case "inet":
+		return true, true, true
+	}
+	return false, false, false
+}
+
+func nftChainName(family, table, chain string) string {
+	return aclName(family + "-" + table + "-" + chain)
+}
+
+func parseNFTRule(exprs []json.RawMessage, v4, v6 bool) (*match, error) {
+	m := newMatch(v4, v6)
+	for _, raw := range exprs {
+		var expr map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &expr); err != nil {
+			return nil, fmt.Errorf("invalid expression: %w", err)
+		}
+		for key, value := range expr {
+			switch key {
+			case "match":
+				var nm nftMatch
+				if err := json.Unmarshal(value, &nm); err != nil {
+					return nil, fmt.Errorf("invalid match: %w", err)
+				}
+				if err := m.applyNFTMatch(nm); err != nil {
+					return nil, err
+				}
+			case "counter":
+			case "accept":
+				m.action, m.hasAction = domain.ACLPermit, true
+			case "drop", "reject":
+				m.action, m.hasAction = domain.ACLDeny, true
+			default:
+				return nil, fmt.Errorf("statement %s", key)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *match) applyNFTMatch(nm nftMatch) error {
+	if nm.Op != "" && nm.Op != "==" && nm.Op != "in" {
+		return fmt.Errorf("match operator %s", nm.Op)
+	}
+
+	var left map[string]json.RawMessage
+	if err := json.Unmarshal(nm.Left, &left); err != nil {
+		return fmt.Errorf("invalid match: %w", err)
+	}
+	if raw, ok := left["&"]; ok {
+		return m.applyNFTFlags(raw, nm.Right)
+	}
+	if raw, ok := left["meta"]; ok {
+		var meta struct {
+			Key string `json:"key"`
+		}
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return fmt.Errorf("invalid meta: %w", err)
+		}
+		switch meta.Key {
+		case "l4proto":
+			return m.applyNFTProto(nm.Right)
+		case "nfproto":
+			family, err := nftString(nm.Right)
+			if err != nil {
+				return err
+			}
+			return m.restrictFamily(family == "ipv6")
+		case "iifname", "oifname", "iif", "oif":
+			return fmt.Errorf("interface match meta %s, apply the ACL to the interface instead", meta.Key)
+		default:
+			return fmt.Errorf("meta %s", meta.Key)
+		}
+	}
+	if _, ok := left["ct"]; ok {
+		return fmt.Errorf("connection tracking match, use the reflect action for stateful rules")
+	}
+	raw, ok := left["payload"]
+	if !ok {
+		return fmt.Errorf("match on %s", string(nm.Left))
+	}
+	var payload nftPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	switch payload.Protocol + " " + payload.Field {
+	case "ip saddr", "ip daddr", "ip6 saddr", "ip6 daddr":
+		prefixes, err := nftPrefixes(nm.Right)
+		if err != nil {
+			return err
+		}
+		return m.addPrefixes(payload.Field == "daddr", prefixes)
+	case "ip protocol", "ip6 nexthdr":
+		return m.applyNFTProto(nm.Right)
+	case "tcp sport", "tcp dport", "udp sport", "udp dport", "th sport", "th dport":
+		if payload.Protocol != "th" {
+			if err := m.setProto(nftProtos[payload.Protocol]); err != nil {
+				return err
+			}
+		}
+		ranges, err := nftRanges(nm.Right, maxPort)
+		if err != nil {
+			return err
+		}
+		if payload.Field == "sport" {
+			m.sport = ranges
+		} else {
+			m.dport = ranges
+		}
+		return nil
+	case "icmp type", "icmp code", "icmpv6 type", "icmpv6 code":
+		if err := m.setProto(nftProtos[payload.Protocol]); err != nil {
+			return err
+		}
+		names := icmp4TypeNames
+		if payload.Protocol == "icmpv6" {
+			names = icmp6TypeNames
+		}
+		ranges, err := nftRangesNamed(nm.Right, maxICMPType, names)
+		if err != nil {
+			return err
+		}
+		if payload.Field == "type" {
+			m.icmpType = ranges
+		} else {
+			m.icmpCode = ranges
+		}
+		return nil
+	case "tcp flags":
+		// a bare flag match tests that the flags are set
+		flags, err := nftFlags(nm.Right)
+		if err != nil {
+			return err
+		}
+		if err := m.setProto(protoTCP); err != nil {
+			return err
+		}
+		m.flagsMask, m.flagsValue = flags, flags
+		return nil
+	}
+	return fmt.Errorf("match on %s %s", payload.Protocol, payload.Field)
+}
+
+// applyNFTFlags handles tcp flags & mask == value
+func (m *match) applyNFTFlags(operands, right json.RawMessage) error {
+	var args []json.RawMessage
+	if err := json.Unmarshal(operands, &args); err != nil || len(args) != 2 {
+		return fmt.Errorf("bitwise match %s", string(operands))
+	}
+	var holder struct {
+		Payload nftPayload `json:"payload"`
+	}
+	if err := json.Unmarshal(args[0], &holder); err != nil || holder.Payload.Protocol != "tcp" || holder.Payload.Field != "flags" {
+		return fmt.Errorf("bitwise match %s", string(args[0]))
+	}
+	mask, err := nftFlags(args[1])
+	if err != nil {
+		return err
+	}
+	value, err := nftFlags(right)
+	if err != nil {
+		return err
+	}
+	if err := m.setProto(protoTCP); err != nil {
+		return err
+	}
+	m.flagsMask, m.flagsValue = mask, value&mask
+	return nil
+}
+
+func (m *match) applyNFTProto(right json.RawMessage) error {
+	var protos []uint8
+	for _, item := range nftSetItems(right) {
+		var proto uint8
+		if err := json.Unmarshal(item, &proto); err != nil {
+			name, err := nftString(item)
+			if err != nil {
+				return err
+			}
+			p, ok := nftProtos[name]
+			if !ok {
+				return fmt.Errorf("unknown protocol %q", name)
+			}
+			proto = p
+		}
+		protos = append(protos, proto)
+	}
+	if len(protos) == 1 {
+		return m.setProto(protos[0])
+	}
+	m.protos = protos
+	return nil
+}
+
+// nftSetItems returns the elements of an anonymous set or the value itself
+func nftSetItems(raw json.RawMessage) []json.RawMessage {
+	var set struct {
+		Set []json.RawMessage `json:"set"`
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) && json.Unmarshal(raw, &set) == nil && set.Set != nil {
+		return set.Set
+	}
+	return []json.RawMessage{raw}
+}
+
+func nftString(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", fmt.Errorf("unsupported value %s", string(raw))
+	}
+	return s, nil
+}
+
+func nftPrefixes(raw json.RawMessage) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range nftSetItems(raw) {
+		var holder struct {
+			Prefix *nftPrefix `json:"prefix"`
+		}
+		if json.Unmarshal(item, &holder) == nil && holder.Prefix != nil {
+			prefix, err := parsePrefix(fmt.Sprintf("%s/%d", holder.Prefix.Addr, holder.Prefix.Len))
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix)
+			continue
+		}
+		s, err := nftString(item)
+		if err != nil {
+			return nil, err
+		}
+		prefix, err := parsePrefix(s)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+func nftRanges(raw json.RawMessage, max uint16) ([]portRange, error) {
+	return nftRangesNamed(raw, max, nil)
+}
+
+// nftRangesNamed reads numbers, {"range": [low, high]} and named values
+func nftRangesNamed(raw json.RawMessage, max uint16, names map[string]uint16) ([]portRange, error) {
+	var ranges []portRange
+	for _, item := range nftSetItems(raw) {
+		var holder struct {
+			Range []json.RawMessage `json:"range"`
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(item), []byte("{")) && json.Unmarshal(item, &holder) == nil && len(holder.Range) == 2 {
+			low, err := nftNumber(holder.Range[0], max, names)
+			if err != nil {
+				return nil, err
+			}
+			high, err := nftNumber(holder.Range[1], max, names)
+			if err != nil {
+				return nil, err
+			}
+			if high < low {
+				return nil, fmt.Errorf("reversed range %s", string(item))
+			}
+			ranges = append(ranges, portRange{low, high})
+			continue
+		}
+		n, err := nftNumber(item, max, names)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, portRange{n, n})
+	}
+	return ranges, nil
+}
+
+func nftNumber(raw json.RawMessage, max uint16, names map[string]uint16) (uint16, error) {
+	var n uint64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		if n > uint64(max) {
+			return 0, fmt.Errorf("value %d is out of range 0-%d", n, max)
+		}
+		return uint16(n), nil
+	}
+	name, err := nftString(raw)
+	if err != nil {
+		return 0, err
+	}
+	if v, ok := names[name]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown value %q", name)
+}
+
+// nftFlags reads a flag name, a list of names or an {"|": [...]} expression
+func nftFlags(raw json.RawMessage) (uint8, error) {
+	var n uint8
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, nil
+	}
+	var names []json.RawMessage
+	var or struct {
+		Or []json.RawMessage `json:"|"`
+	}
+	switch {
+	case json.Unmarshal(raw, &names) == nil:
+	case json.Unmarshal(raw, &or) == nil && or.Or != nil:
+		names = or.Or
+	default:
+		names = []json.RawMessage{raw}
+	}
+
+	var flags uint8
+	for _, item := range names {
+		if strings.HasPrefix(strings.TrimSpace(string(item)), "{") {
+			nested, err := nftFlags(item)
+			if err != nil {
+				return 0, err
+			}
+			flags |= nested
+			continue
+		}
+		name, err := nftString(item)
+		if err != nil {
+			return 0, err
+		}
+		flag, ok := nftTCPFlags[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown tcp flag %q", name)
+		}
+		flags |= flag
+	}
+	return flags, nil
+}
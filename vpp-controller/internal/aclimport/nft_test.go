@@ -0,0 +1,107 @@
+package aclimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNFTJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      map[string][]string
+		wantSkips int
+		wantDeny  bool
+	}{
+		{
+			name: "sets",
+			input: `{"nftables": [
+  {"chain": {"family": "ip", "table": "filter", "name": "input", "type": "filter", "hook": "input", "prio": 0, "policy": "drop"}},
+  {"rule": {"family": "ip", "table": "filter", "chain": "input", "handle": 2, "expr": [
+    {"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "saddr"}}, "right": {"set": [{"prefix": {"addr": "10.0.0.0", "len": 8}}, "192.168.1.1"]}}},
+    {"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": {"set": [22, {"range": [8000, 8080]}]}}},
+    {"counter": {"packets": 0, "bytes": 0}},
+    {"accept": null}
+  ]}}
+]}`,
+			want: map[string][]string{
+				"ip-filter-input": {
+					"permit tcp 10.0.0.0/8 any eq 22",
+					"permit tcp 10.0.0.0/8 any range 8000 8080",
+					"permit tcp host 192.168.1.1 any eq 22",
+					"permit tcp host 192.168.1.1 any range 8000 8080",
+					"deny ip any any",
+				},
+			},
+		},
+		{
+			name: "inet",
+			input: `{"nftables": [
+  {"chain": {"family": "inet", "table": "fw", "name": "forward", "type": "filter", "hook": "forward", "prio": 0, "policy": "accept"}},
+  {"rule": {"family": "inet", "table": "fw", "chain": "forward", "handle": 3, "expr": [
+    {"match": {"op": "==", "left": {"meta": {"key": "l4proto"}}, "right": "icmpv6"}},
+    {"match": {"op": "==", "left": {"payload": {"protocol": "icmpv6", "field": "type"}}, "right": "echo-request"}},
+    {"drop": null}
+  ]}}
+]}`,
+			want: map[string][]string{
+				"inet-fw-forward": {
+					"deny icmpv6 any6 any6 type 128",
+					"permit ip any any",
+					"permit ip any6 any6",
+				},
+			},
+		},
+		{
+			name: "non-filter chain",
+			input: `{"nftables": [
+  {"chain": {"family": "ip", "table": "nat", "name": "prerouting", "type": "nat", "hook": "prerouting", "prio": -100, "policy": "accept"}},
+  {"rule": {"family": "ip", "table": "nat", "chain": "prerouting", "handle": 4, "expr": [
+    {"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": 80}},
+    {"dnat": {"addr": "10.0.0.2"}}
+  ]}},
+  {"chain": {"family": "ip", "table": "mangle", "name": "output", "type": "route", "hook": "output", "prio": -150, "policy": "accept"}},
+  {"rule": {"family": "ip", "table": "mangle", "chain": "output", "handle": 5, "expr": [
+    {"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "daddr"}}, "right": "10.0.0.9"}},
+    {"drop": null}
+  ]}}
+]}`,
+			want:      map[string][]string{},
+			wantSkips: 2,
+			wantDeny:  true,
+		},
+		{
+			name: "skipped accept",
+			input: `{"nftables": [
+  {"chain": {"family": "ip", "table": "filter", "name": "input", "type": "filter", "hook": "input", "prio": 0, "policy": "accept"}},
+  {"rule": {"family": "ip", "table": "filter", "chain": "input", "handle": 2, "expr": [
+    {"match": {"op": "==", "left": {"meta": {"key": "iifname"}}, "right": "lo"}},
+    {"accept": null}
+  ]}},
+  {"rule": {"family": "arp", "table": "filter", "chain": "input", "handle": 3, "expr": [{"accept": null}]}}
+]}`,
+			want: map[string][]string{
+				"ip-filter-input": {"permit ip any any"},
+			},
+			wantSkips: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseNFTJSON(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseNFTJSON() error = %v", err)
+			}
+			if got := chainRules(result.Chains); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNFTJSON() rules = %v, want %v", got, tt.want)
+			}
+			if len(result.Issues) != tt.wantSkips {
+				t.Errorf("ParseNFTJSON() issues = %+v, want %d", result.Issues, tt.wantSkips)
+			}
+			if result.SkipsDeny() != tt.wantDeny {
+				t.Errorf("SkipsDeny() = %v, want %v", result.SkipsDeny(), tt.wantDeny)
+			}
+		})
+	}
+}
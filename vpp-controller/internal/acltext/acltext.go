@@ -0,0 +1,487 @@
+// Package acltext compiles ACL rules written one per line in a firewall-like
+// syntax into domain rules and renders domain rules back into it:
+//
+//	<action> <proto> <src> [<ports>] <dst> [<ports>] [<tcp flags>]
+//	<action> icmp|icmpv6 <src> <dst> [type N[-M]] [code N[-M]]
+//
+// action is permit, deny or reflect; proto is ip, a protocol name or a number;
+// an address is any, any4, any6, host A, A/len or a bare address; ports are
+// eq N, lt N, gt N or range N M; tcp flags are established or
+// flags VALUE/MASK with comma separated flag names. Text after # is ignored.
+//
+//	permit tcp 10.0.0.0/8 any eq 443 established
+package acltext
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+const (
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoICMPv6 = 58
+
+	maxPort     = 65535
+	maxICMPType = 255
+
+	// established matches packets of an open TCP connection, VPP compares
+	// flags&mask with value so only the ACK bit is checked
+	flagACK = 0x10
+)
+
+var protoNames = map[string]uint8{
+	"ip":     0,
+	"icmp":   protoICMP,
+	"tcp":    protoTCP,
+	"udp":    protoUDP,
+	"gre":    47,
+	"esp":    50,
+	"ah":     51,
+	"icmpv6": protoICMPv6,
+	"ospf":   89,
+	"sctp":   132,
+}
+
+var tcpFlagNames = []string{"fin", "syn", "rst", "psh", "ack", "urg", "ece", "cwr"}
+
+// Parse compiles every non-empty line of text into a rule, errors carry the
+// line number
+func Parse(text string) ([]domain.ACLRule, error) {
+	var rules []domain.ACLRule
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rule, err := ParseRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("rules list is empty")
+	}
+	return rules, nil
+}
+
+// ParseRule compiles a single rule
+func ParseRule(line string) (domain.ACLRule, error) {
+	p := &parser{tokens: strings.Fields(line)}
+
+	action, err := domain.ParseACLAction(p.next())
+	if err != nil {
+		return domain.ACLRule{}, err
+	}
+	rule := domain.ACLRule{Action: action}
+
+	if rule.Proto, err = parseProto(p.next()); err != nil {
+		return domain.ACLRule{}, err
+	}
+	ported := rule.Proto == protoTCP || rule.Proto == protoUDP
+	icmp := rule.Proto == protoICMP || rule.Proto == protoICMPv6
+
+	src, err := p.address()
+	if err != nil {
+		return domain.ACLRule{}, fmt.Errorf("source: %w", err)
+	}
+	rule.SrcPortLow, rule.SrcPortHigh = 0, maxPort
+	if ported && p.isPortOp() {
+		if rule.SrcPortLow, rule.SrcPortHigh, err = p.ports(); err != nil {
+			return domain.ACLRule{}, fmt.Errorf("source port: %w", err)
+		}
+	}
+	dst, err := p.address()
+	if err != nil {
+		return domain.ACLRule{}, fmt.Errorf("destination: %w", err)
+	}
+	rule.DstPortLow, rule.DstPortHigh = 0, maxPort
+	if ported && p.isPortOp() {
+		if rule.DstPortLow, rule.DstPortHigh, err = p.ports(); err != nil {
+			return domain.ACLRule{}, fmt.Errorf("destination port: %w", err)
+		}
+	}
+	if rule.Src, rule.Dst, err = resolveFamilies(rule.Proto, src, dst); err != nil {
+		return domain.ACLRule{}, err
+	}
+
+	if icmp {
+		// VPP keeps the ICMP type in the source and the code in the
+		// destination port range
+		rule.SrcPortLow, rule.SrcPortHigh = 0, maxICMPType
+		rule.DstPortLow, rule.DstPortHigh = 0, maxICMPType
+		if p.peek() == "type" {
+			p.next()
+			if rule.SrcPortLow, rule.SrcPortHigh, err = parseICMPRange(p.next()); err != nil {
+				return domain.ACLRule{}, fmt.Errorf("icmp type: %w", err)
+			}
+		}
+		if p.peek() == "code" {
+			p.next()
+			if rule.DstPortLow, rule.DstPortHigh, err = parseICMPRange(p.next()); err != nil {
+				return domain.ACLRule{}, fmt.Errorf("icmp code: %w", err)
+			}
+		}
+	}
+
+	if rule.Proto == protoTCP {
+		switch p.peek() {
+		case "established":
+			p.next()
+			rule.TCPFlagsMask, rule.TCPFlagsValue = flagACK, flagACK
+		case "flags":
+			p.next()
+			if rule.TCPFlagsValue, rule.TCPFlagsMask, err = parseTCPFlags(p.next()); err != nil {
+				return domain.ACLRule{}, err
+			}
+		}
+	}
+
+	if rest := p.rest(); len(rest) > 0 {
+		return domain.ACLRule{}, fmt.Errorf("unexpected %q", strings.Join(rest, " "))
+	}
+	return rule, nil
+}
+
+// address is a parsed address token, an any without a family takes the
+// family of the other side of the rule
+type address struct {
+	prefix    domain.IPWithPrefix
+	anyFamily bool
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) rest() []string {
+	return p.tokens[p.pos:]
+}
+
+func (p *parser) address() (address, error) {
+	token := p.next()
+	switch token {
+	case "":
+		return address{}, fmt.Errorf("missing address")
+	case "any":
+		return address{prefix: domain.IPWithPrefix{Address: "0.0.0.0"}, anyFamily: true}, nil
+	case "any4":
+		return address{prefix: domain.IPWithPrefix{Address: "0.0.0.0"}}, nil
+	case "any6":
+		return address{prefix: domain.IPWithPrefix{Address: "::"}}, nil
+	case "host":
+		token = p.next()
+		if strings.Contains(token, "/") {
+			return address{}, fmt.Errorf("host %q must not have a prefix length", token)
+		}
+	}
+
+	if !strings.Contains(token, "/") {
+		ip := net.ParseIP(token)
+		if ip == nil {
+			return address{}, fmt.Errorf("invalid address %q", token)
+		}
+		return address{prefix: domain.IPWithPrefix{Address: ip.String(), Prefix: hostPrefix(ip)}}, nil
+	}
+	ip, ipNet, err := net.ParseCIDR(token)
+	if err != nil {
+		return address{}, fmt.Errorf("invalid prefix %q", token)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return address{}, fmt.Errorf("prefix %q has host bits set", token)
+	}
+	ones, _ := ipNet.Mask.Size()
+	return address{prefix: domain.IPWithPrefix{Address: ipNet.IP.String(), Prefix: uint8(ones)}}, nil
+}
+
+func (p *parser) isPortOp() bool {
+	switch p.peek() {
+	case "eq", "lt", "gt", "range":
+		return true
+	}
+	return false
+}
+
+func (p *parser) ports() (uint16, uint16, error) {
+	op := p.next()
+	first, err := parseNumber(p.next(), maxPort)
+	if err != nil {
+		return 0, 0, err
+	}
+	switch op {
+	case "eq":
+		return first, first, nil
+	case "lt":
+		if first == 0 {
+			return 0, 0, fmt.Errorf("no port is lower than 0")
+		}
+		return 0, first - 1, nil
+	case "gt":
+		if first == maxPort {
+			return 0, 0, fmt.Errorf("no port is greater than %d", maxPort)
+		}
+		return first + 1, maxPort, nil
+	default:
+		last, err := parseNumber(p.next(), maxPort)
+		if err != nil {
+			return 0, 0, err
+		}
+		if last < first {
+			return 0, 0, fmt.Errorf("range %d %d is reversed", first, last)
+		}
+		return first, last, nil
+	}
+}
+
+func parseProto(token string) (uint8, error) {
+	if token == "any" {
+		return 0, nil
+	}
+	if proto, ok := protoNames[token]; ok {
+		return proto, nil
+	}
+	proto, err := parseNumber(token, 255)
+	if err != nil {
+		return 0, fmt.Errorf("unknown protocol %q", token)
+	}
+	return uint8(proto), nil
+}
+
+func parseNumber(token string, max uint16) (uint16, error) {
+	if token == "" {
+		return 0, fmt.Errorf("missing number")
+	}
+	n, err := strconv.ParseUint(token, 0, 16)
+	if err != nil || n > uint64(max) {
+		return 0, fmt.Errorf("invalid number %q, expected 0-%d", token, max)
+	}
+	return uint16(n), nil
+}
+
+func parseICMPRange(token string) (uint16, uint16, error) {
+	firstStr, lastStr, isRange := strings.Cut(token, "-")
+	first, err := parseNumber(firstStr, maxICMPType)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return first, first, nil
+	}
+	last, err := parseNumber(lastStr, maxICMPType)
+	if err != nil {
+		return 0, 0, err
+	}
+	if last < first {
+		return 0, 0, fmt.Errorf("range %q is reversed", token)
+	}
+	return first, last, nil
+}
+
+func parseTCPFlags(token string) (value, mask uint8, err error) {
+	valueStr, maskStr, ok := strings.Cut(token, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("tcp flags %q must be VALUE/MASK", token)
+	}
+	if value, err = parseFlagSet(valueStr); err != nil {
+		return 0, 0, err
+	}
+	if mask, err = parseFlagSet(maskStr); err != nil {
+		return 0, 0, err
+	}
+	if value&^mask != 0 {
+		return 0, 0, fmt.Errorf("tcp flags %q set flags outside of the mask", token)
+	}
+	return value, mask, nil
+}
+
+func parseFlagSet(token string) (uint8, error) {
+	if token == "none" {
+		return 0, nil
+	}
+	if n, err := strconv.ParseUint(token, 0, 8); err == nil {
+		return uint8(n), nil
+	}
+	var flags uint8
+	for _, name := range strings.Split(token, ",") {
+		bit := -1
+		for i, flag := range tcpFlagNames {
+			if flag == name {
+				bit = i
+			}
+		}
+		if bit < 0 {
+			return 0, fmt.Errorf("unknown tcp flag %q", name)
+		}
+		flags |= 1 << bit
+	}
+	return flags, nil
+}
+
+// resolveFamilies gives an any the family of the other side, or of the
+// protocol when both sides are any
+func resolveFamilies(proto uint8, src, dst address) (domain.IPWithPrefix, domain.IPWithPrefix, error) {
+	srcV6, dstV6 := isIPv6(src.prefix), isIPv6(dst.prefix)
+	switch {
+	case src.anyFamily && dst.anyFamily && proto == protoICMPv6:
+		src.prefix.Address, srcV6 = "::", true
+		dst.prefix.Address, dstV6 = "::", true
+	case src.anyFamily && !dst.anyFamily && dstV6:
+		src.prefix.Address, srcV6 = "::", true
+	case dst.anyFamily && !src.anyFamily && srcV6:
+		dst.prefix.Address, dstV6 = "::", true
+	}
+	if srcV6 != dstV6 {
+		return domain.IPWithPrefix{}, domain.IPWithPrefix{}, fmt.Errorf("source and destination address families differ")
+	}
+	switch {
+	case proto == protoICMP && srcV6:
+		return domain.IPWithPrefix{}, domain.IPWithPrefix{}, fmt.Errorf("icmp needs IPv4 addresses, use icmpv6")
+	case proto == protoICMPv6 && !srcV6:
+		return domain.IPWithPrefix{}, domain.IPWithPrefix{}, fmt.Errorf("icmpv6 needs IPv6 addresses, use icmp")
+	}
+	return src.prefix, dst.prefix, nil
+}
+
+func isIPv6(prefix domain.IPWithPrefix) bool {
+	return strings.Contains(prefix.Address, ":")
+}
+
+func hostPrefix(ip net.IP) uint8 {
+	if ip.To4() != nil {
+		return 32
+	}
+	return 128
+}
+
+// FormatRules renders rules one per line
+func FormatRules(rules []domain.ACLRule) string {
+	var b strings.Builder
+	for _, rule := range rules {
+		b.WriteString(Format(rule))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Format renders a rule in the syntax accepted by ParseRule, rules read back
+// from VPP render to text that compiles into the same rule
+func Format(rule domain.ACLRule) string {
+	parts := []string{rule.Action.String(), formatProto(rule.Proto)}
+
+	ported := rule.Proto == protoTCP || rule.Proto == protoUDP
+	parts = append(parts, formatAddress(rule.Src, rule.Dst))
+	if ported {
+		parts = appendPorts(parts, rule.SrcPortLow, rule.SrcPortHigh)
+	}
+	parts = append(parts, formatAddress(rule.Dst, rule.Src))
+	if ported {
+		parts = appendPorts(parts, rule.DstPortLow, rule.DstPortHigh)
+	}
+
+	if rule.Proto == protoICMP || rule.Proto == protoICMPv6 {
+		if rule.SrcPortLow != 0 || rule.SrcPortHigh < maxICMPType {
+			parts = append(parts, "type", formatICMPRange(rule.SrcPortLow, rule.SrcPortHigh))
+		}
+		if rule.DstPortLow != 0 || rule.DstPortHigh < maxICMPType {
+			parts = append(parts, "code", formatICMPRange(rule.DstPortLow, rule.DstPortHigh))
+		}
+	}
+
+	if rule.Proto == protoTCP && rule.TCPFlagsMask != 0 {
+		if rule.TCPFlagsMask == flagACK && rule.TCPFlagsValue == flagACK {
+			parts = append(parts, "established")
+		} else {
+			parts = append(parts, "flags", formatFlagSet(rule.TCPFlagsValue)+"/"+formatFlagSet(rule.TCPFlagsMask))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatProto(proto uint8) string {
+	for name, p := range protoNames {
+		if p == proto {
+			return name
+		}
+	}
+	return strconv.Itoa(int(proto))
+}
+
+func formatAddress(prefix, other domain.IPWithPrefix) string {
+	v6 := isIPv6(prefix)
+	switch {
+	case prefix.Prefix == 0 && v6:
+		// any takes the family of the other side, which must be concrete
+		if isIPv6(other) && other.Prefix != 0 {
+			return "any"
+		}
+		return "any6"
+	case prefix.Prefix == 0:
+		return "any"
+	case v6 && prefix.Prefix == 128, !v6 && prefix.Prefix == 32:
+		return "host " + prefix.Address
+	default:
+		return fmt.Sprintf("%s/%d", prefix.Address, prefix.Prefix)
+	}
+}
+
+func appendPorts(parts []string, low, high uint16) []string {
+	switch {
+	case low == 0 && high == maxPort:
+		return parts
+	case low == high:
+		return append(parts, "eq", strconv.Itoa(int(low)))
+	case low == 0:
+		return append(parts, "lt", strconv.Itoa(int(high)+1))
+	case high == maxPort:
+		return append(parts, "gt", strconv.Itoa(int(low)-1))
+	default:
+		return append(parts, "range", strconv.Itoa(int(low)), strconv.Itoa(int(high)))
+	}
+}
+
+func formatICMPRange(low, high uint16) string {
+	if low == high {
+		return strconv.Itoa(int(low))
+	}
+	return fmt.Sprintf("%d-%d", low, high)
+}
+
+func formatFlagSet(flags uint8) string {
+	if flags == 0 {
+		return "none"
+	}
+	var names []string
+	for i, name := range tcpFlagNames {
+		if flags&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
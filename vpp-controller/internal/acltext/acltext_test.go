@@ -0,0 +1,97 @@
+package acltext
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"permit ip any any", "permit ip any any"},
+		{"deny tcp 10.0.0.0/8 any eq 443 established", "deny tcp 10.0.0.0/8 any eq 443 established"},
+		{"permit tcp host 10.0.0.1 range 1024 65535 192.168.0.0/16 lt 1024", "permit tcp host 10.0.0.1 gt 1023 192.168.0.0/16 lt 1024"},
+		{"permit udp any6 2001:db8::/32 eq 53", "permit udp any 2001:db8::/32 eq 53"},
+		{"reflect tcp 2001:db8::1 any gt 1023 flags syn/syn,ack", "reflect tcp host 2001:db8::1 any gt 1023 flags syn/syn,ack"},
+		{"permit icmp any any type 8 code 0", "permit icmp any any type 8 code 0"},
+		{"permit icmp 10.0.0.0/24 any type 0-8", "permit icmp 10.0.0.0/24 any type 0-8"},
+		{"permit icmpv6 any any type 128-129", "permit icmpv6 any6 any6 type 128-129"},
+		{"deny 47 any any4", "deny gre any any"},
+		{"deny any6 any6", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			rule, err := ParseRule(tt.line)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("ParseRule() = %+v, want error", rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule() error = %v", err)
+			}
+			text := Format(rule)
+			if text != tt.want {
+				t.Errorf("Format() = %q, want %q", text, tt.want)
+			}
+			again, err := ParseRule(text)
+			if err != nil {
+				t.Fatalf("ParseRule(%q) error = %v", text, err)
+			}
+			if !reflect.DeepEqual(again, rule) {
+				t.Errorf("round trip = %+v, want %+v", again, rule)
+			}
+		})
+	}
+}
+
+func TestParseICMPFamilies(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantSrc string
+		wantErr bool
+	}{
+		{line: "permit icmpv6 any any", wantSrc: "::"},
+		{line: "permit icmp any any", wantSrc: "0.0.0.0"},
+		{line: "permit icmpv6 any 2001:db8::/32", wantSrc: "::"},
+		{line: "permit icmp any 2001:db8::/32", wantErr: true},
+		{line: "permit icmp any6 any6", wantErr: true},
+		{line: "permit icmpv6 10.0.0.0/8 any", wantErr: true},
+		{line: "permit icmpv6 any4 any4", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			rule, err := ParseRule(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRule() = %+v, want error", rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule() error = %v", err)
+			}
+			if rule.Src.Address != tt.wantSrc || rule.Dst.Prefix == 0 && rule.Dst.Address != tt.wantSrc {
+				t.Errorf("ParseRule() src %s dst %s, want family of %s", rule.Src.Address, rule.Dst.Address, tt.wantSrc)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	rules, err := Parse("# web\npermit tcp any any eq 80\n\ndeny ip any any # rest\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rules) != 2 || rules[1].Action != domain.ACLDeny {
+		t.Fatalf("Parse() = %+v", rules)
+	}
+	if _, err := Parse("permit tcp any any eq 80\npermit tcp any any eq http\n"); err == nil || err.Error()[:7] != "line 2:" {
+		t.Errorf("Parse() error = %v, want line 2 error", err)
+	}
+}
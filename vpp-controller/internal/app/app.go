@@ -14,21 +14,30 @@ import (
 	"github.com/NikolayStepanov/RapidVPP/internal/mw"
 	"github.com/NikolayStepanov/RapidVPP/internal/server"
 	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/abf"
 	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/acl"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/aclgroup"
 	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/info"
 	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/interfaces"
 	ipServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/ip"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/ip6nd"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/macip"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/mpls"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/mroute"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/neighbor"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/srv6"
 	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
 	"github.com/fsnotify/fsnotify"
 	"go.uber.org/zap"
 )
 
 type App struct {
-	config    *config.Config
-	server    *server.Server
-	services  *service.Services
-	handler   *handlers.Handler
-	vppClient *vpp.Client
+	config      *config.Config
+	server      *server.Server
+	services    *service.Services
+	handler     *handlers.Handler
+	vppClient   *vpp.Client
+	statsClient *vpp.StatsClient
 }
 
 func NewApp(config *config.Config) (*App, error) {
@@ -36,21 +45,36 @@ func NewApp(config *config.Config) (*App, error) {
 	if err != nil {
 		log.Fatalf("failed to create VPP client: %v", err)
 	}
+	// ACL counters are optional, the rest of the API works without them
+	statsClient, err := vpp.NewStatsClient(config.VPP.StatsSocket)
+	if err != nil {
+		logger.Warn("VPP stats segment unavailable, acl counters are disabled", zap.Error(err))
+		statsClient = nil
+	}
 
 	infoService := info.NewService(VPPClient)
 	interfaceService := interfaces.NewService(VPPClient)
 	IPService := ipServ.NewService(VPPClient)
-	aclService := acl.NewService(VPPClient)
+	aclService := acl.NewService(VPPClient, statsClient, interfaceService)
+	neighborService := neighbor.NewService(VPPClient)
+	ip6ndService := ip6nd.NewService(VPPClient)
+	mrouteService := mroute.NewService(VPPClient)
+	mplsService := mpls.NewService(VPPClient)
+	srv6Service := srv6.NewService(VPPClient, IPService)
+	abfService := abf.NewService(VPPClient, aclService)
+	macipService := macip.NewService(VPPClient)
+	aclGroupService := aclgroup.NewService(aclService)
 
-	services := service.NewServices(infoService, interfaceService, IPService, aclService)
-	handler := handlers.NewHandler(infoService, interfaceService, IPService, aclService)
+	services := service.NewServices(infoService, interfaceService, IPService, aclService, neighborService, ip6ndService, mrouteService, mplsService, srv6Service, abfService, macipService, aclGroupService)
+	handler := handlers.NewHandler(infoService, interfaceService, IPService, aclService, neighborService, ip6ndService, mrouteService, mplsService, srv6Service, abfService, macipService, aclGroupService)
 	server := server.NewServer(config, mw.LoggerMiddleware(handler))
 	return &App{
-		config:    config,
-		server:    server,
-		handler:   handler,
-		vppClient: VPPClient,
-		services:  services,
+		config:      config,
+		server:      server,
+		handler:     handler,
+		vppClient:   VPPClient,
+		statsClient: statsClient,
+		services:    services,
 	}, nil
 }
 
@@ -74,12 +98,25 @@ func Run() {
 		logger.Fatal("error new app", zap.Error(err))
 	}
 	defer app.vppClient.Close()
+	if app.statsClient != nil {
+		defer app.statsClient.Close()
+	}
 
 	err = app.services.IP.InitVRFCache(ctx)
 	if err != nil {
 		logger.Fatal("error init VRF cache", zap.Error(err))
 	}
 
+	err = app.services.Interface.InitNameCache(ctx)
+	if err != nil {
+		logger.Fatal("error init interface name cache", zap.Error(err))
+	}
+
+	err = app.services.ACL.EnableCounters(ctx)
+	if err != nil {
+		logger.Warn("acl counters are disabled", zap.Error(err))
+	}
+
 	go func() {
 		defer cancel()
 		if err := app.server.Run(); err != nil {
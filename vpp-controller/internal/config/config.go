@@ -34,6 +34,7 @@ type (
 	}
 	VPPConfig struct {
 		Socket         string `yaml:"socket"`
+		StatsSocket    string `yaml:"stats_socket"`
 		StreamPoolSize int    `yaml:"stream_pool_size"`
 	}
 )
@@ -56,6 +57,7 @@ func Init() *Config {
 		defaultLoggerOutputPaths,
 	}
 	cfg.VPP.Socket = "/run/vpp/api.sock"
+	cfg.VPP.StatsSocket = "/run/vpp/stats.sock"
 	return &cfg
 }
 
@@ -0,0 +1,215 @@
+package abf
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/ip"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	abfServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/abf"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	abf      service.ABF
+	resolver service.InterfaceResolver
+}
+
+func NewHandler(abf service.ABF, resolver service.InterfaceResolver) *Handler {
+	return &Handler{abf: abf, resolver: resolver}
+}
+
+func (h *Handler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.abf.ListPolicies(r.Context())
+	if err != nil {
+		logger.Error("Failed to list ABF policies", zap.Error(err))
+		http.Error(w, "Failed to list ABF policies", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, PoliciesToResponse(policies))
+}
+
+func (h *Handler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePolicyID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	policy, err := h.abf.GetPolicy(r.Context(), id)
+	switch {
+	case errors.Is(err, abfServ.ErrNotFound):
+		logger.Warn("ABF policy not found", zap.Uint32("id", id))
+		http.Error(w, "ABF policy not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to get ABF policy", zap.Uint32("id", id), zap.Error(err))
+		http.Error(w, "Failed to get ABF policy", http.StatusInternalServerError)
+	default:
+		response.JSON(w, PolicyToResponse(policy))
+	}
+}
+
+func (h *Handler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req PolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := ip.ResolveNextHops(r.Context(), h.resolver, req.Paths); err != nil {
+		response.ResolveError(w, "Invalid path interface", err)
+		return
+	}
+	policy, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid ABF policy", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.abf.CreatePolicy(r.Context(), policy)
+	switch {
+	case errors.Is(err, abfServ.ErrACLNotFound):
+		logger.Warn("ABF policy ACL not found", zap.Uint32("acl", req.ACLID))
+		http.Error(w, "ACL not found", http.StatusNotFound)
+	case errors.Is(err, abfServ.ErrExists):
+		logger.Warn("ABF policy already exists", zap.Uint32("id", policy.ID))
+		http.Error(w, "ABF policy already exists", http.StatusConflict)
+	case err != nil:
+		logger.Error("Failed to create ABF policy", zap.Uint32("id", policy.ID), zap.Error(err))
+		http.Error(w, "Failed to create ABF policy", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (h *Handler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePolicyID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	err := h.abf.DeletePolicy(r.Context(), id)
+	switch {
+	case errors.Is(err, abfServ.ErrNotFound):
+		logger.Warn("ABF policy not found", zap.Uint32("id", id))
+		http.Error(w, "ABF policy not found", http.StatusNotFound)
+	case errors.Is(err, abfServ.ErrInUse):
+		logger.Warn("ABF policy is attached", zap.Uint32("id", id))
+		http.Error(w, "ABF policy is attached to interfaces, detach it first", http.StatusConflict)
+	case err != nil:
+		logger.Error("Failed to delete ABF policy", zap.Uint32("id", id), zap.Error(err))
+		http.Error(w, "Failed to delete ABF policy", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, err := ParseAttachmentFilter(query)
+	if err != nil {
+		logger.Warn("Invalid ABF attachment filter", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ifStr := query.Get("interface"); ifStr != "" {
+		ifIndex, err := h.resolver.ResolveInterface(r.Context(), ifStr)
+		if err != nil {
+			response.ResolveError(w, "Invalid interface", err, zap.String("interface", ifStr))
+			return
+		}
+		filter.InterfaceID = &ifIndex
+	}
+
+	attachments, err := h.abf.ListAttachments(r.Context(), filter)
+	if err != nil {
+		logger.Error("Failed to list ABF attachments", zap.Error(err))
+		http.Error(w, "Failed to list ABF attachments", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, AttachmentsToResponse(attachments))
+}
+
+func (h *Handler) Attach(w http.ResponseWriter, r *http.Request) {
+	var req AttachRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	attachment, err := req.ToDomain(ifIndex)
+	if err != nil {
+		logger.Warn("Invalid ABF attachment", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.abf.Attach(r.Context(), attachment)
+	switch {
+	case errors.Is(err, abfServ.ErrNotFound):
+		logger.Warn("ABF policy not found", zap.Uint32("id", attachment.PolicyID))
+		http.Error(w, "ABF policy not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to attach ABF policy", zap.Error(err))
+		http.Error(w, "Failed to attach ABF policy", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (h *Handler) Detach(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	policyID, ok := parsePolicyID(w, r, "policy")
+	if !ok {
+		return
+	}
+	isIPv6, err := parseOptionalAF(r.URL.Query().Get("af"))
+	if err != nil {
+		logger.Warn("Invalid af parameter", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.abf.Detach(r.Context(), domain.ABFAttachment{
+		PolicyID:    policyID,
+		InterfaceID: ifIndex,
+		IPv6:        isIPv6,
+	})
+	switch {
+	case errors.Is(err, abfServ.ErrAttachmentNotFound):
+		logger.Warn("ABF attachment not found", zap.Uint32("policy", policyID), zap.Uint32("interface", ifIndex))
+		http.Error(w, "ABF attachment not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to detach ABF policy", zap.Error(err))
+		http.Error(w, "Failed to detach ABF policy", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parsePolicyID(w http.ResponseWriter, r *http.Request, name string) (uint32, bool) {
+	idStr := r.PathValue(name)
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid ABF policy ID in request", zap.String(name, idStr), zap.Error(err))
+		http.Error(w, "Invalid ABF policy ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint32(id), true
+}
@@ -0,0 +1,100 @@
+package abf
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/ip"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+func (r *PolicyRequest) ToDomain() (domain.ABFPolicy, error) {
+	if len(r.Paths) == 0 {
+		return domain.ABFPolicy{}, fmt.Errorf("policy requires at least one path")
+	}
+	nextHops, err := ip.NextHopsToDomain(r.Paths)
+	if err != nil {
+		return domain.ABFPolicy{}, err
+	}
+	return domain.ABFPolicy{
+		ID:       r.ID,
+		ACLID:    domain.AclID(r.ACLID),
+		NextHops: nextHops,
+	}, nil
+}
+
+func (r *AttachRequest) ToDomain(ifIndex uint32) (domain.ABFAttachment, error) {
+	isIPv6, err := parseOptionalAF(r.AF)
+	if err != nil {
+		return domain.ABFAttachment{}, err
+	}
+	return domain.ABFAttachment{
+		PolicyID:    r.PolicyID,
+		InterfaceID: ifIndex,
+		Priority:    r.Priority,
+		IPv6:        isIPv6,
+	}, nil
+}
+
+// ParseAttachmentFilter reads the policy and af query parameters, the
+// interface is resolved by the handler
+func ParseAttachmentFilter(query url.Values) (domain.ABFAttachmentFilter, error) {
+	var filter domain.ABFAttachmentFilter
+	if policyStr := query.Get("policy"); policyStr != "" {
+		policy, err := strconv.ParseUint(policyStr, 10, 32)
+		if err != nil {
+			return filter, fmt.Errorf("invalid policy: %q", policyStr)
+		}
+		id := uint32(policy)
+		filter.PolicyID = &id
+	}
+	if af := query.Get("af"); af != "" {
+		isIPv6, err := ip.ParseAF(af)
+		if err != nil {
+			return filter, err
+		}
+		filter.IPv6 = &isIPv6
+	}
+	return filter, nil
+}
+
+func parseOptionalAF(af string) (bool, error) {
+	if af == "" {
+		return false, nil
+	}
+	return ip.ParseAF(af)
+}
+
+func PolicyToResponse(policy domain.ABFPolicy) PolicyResponse {
+	return PolicyResponse{
+		ID:    policy.ID,
+		ACLID: uint32(policy.ACLID),
+		Paths: policy.NextHops,
+	}
+}
+
+func PoliciesToResponse(policies []domain.ABFPolicy) []PolicyResponse {
+	res := make([]PolicyResponse, 0, len(policies))
+	for _, policy := range policies {
+		res = append(res, PolicyToResponse(policy))
+	}
+	return res
+}
+
+func AttachmentsToResponse(attachments []domain.ABFAttachment) []AttachmentResponse {
+	res := make([]AttachmentResponse, 0, len(attachments))
+	for _, a := range attachments {
+		af := "ip4"
+		if a.IPv6 {
+			af = "ip6"
+		}
+		res = append(res, AttachmentResponse{
+			PolicyID:    a.PolicyID,
+			InterfaceID: a.InterfaceID,
+			Priority:    a.Priority,
+			AF:          af,
+		})
+	}
+	return res
+}
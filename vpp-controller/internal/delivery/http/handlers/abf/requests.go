@@ -0,0 +1,17 @@
+package abf
+
+import "github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/ip"
+
+type PolicyRequest struct {
+	ID    uint32              `json:"id"`
+	ACLID uint32              `json:"acl_id"`
+	Paths []ip.NextHopRequest `json:"paths"`
+}
+
+type AttachRequest struct {
+	PolicyID uint32 `json:"policy_id"`
+	// Priority orders the policies of an interface, lower first
+	Priority uint32 `json:"priority"`
+	// AF is ip4 (default) or ip6
+	AF string `json:"af,omitempty"`
+}
@@ -0,0 +1,16 @@
+package abf
+
+import "github.com/NikolayStepanov/RapidVPP/internal/domain"
+
+type PolicyResponse struct {
+	ID    uint32           `json:"id"`
+	ACLID uint32           `json:"acl_id"`
+	Paths []domain.NextHop `json:"paths"`
+}
+
+type AttachmentResponse struct {
+	PolicyID    uint32 `json:"policy_id"`
+	InterfaceID uint32 `json:"interface_id"`
+	Priority    uint32 `json:"priority"`
+	AF          string `json:"af"`
+}
@@ -2,36 +2,56 @@ package acl
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 
+	"github.com/NikolayStepanov/RapidVPP/internal/aclimport"
+	"github.com/NikolayStepanov/RapidVPP/internal/acltext"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	aclServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/acl"
+	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/aclgroup"
 	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
 	"go.uber.org/zap"
 )
 
+const (
+	// maxTextBodySize limits text/plain rule bodies
+	maxTextBodySize = 1 << 20
+	// maxImportBodySize limits imported rulesets
+	maxImportBodySize = 16 << 20
+)
+
 type Handler struct {
-	acl service.ACL
+	acl      service.ACL
+	groups   service.ACLGroup
+	resolver service.InterfaceResolver
 }
 
-func NewHandler(acl service.ACL) *Handler {
-	return &Handler{acl: acl}
+func NewHandler(acl service.ACL, groups service.ACLGroup, resolver service.InterfaceResolver) *Handler {
+	return &Handler{acl: acl, groups: groups, resolver: resolver}
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Warn("Invalid request body", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-	}
-	aclRules, err := ConvertRulesRequestToDomain(req.Rules)
+	aclRules, err := decodeRules(r, &req.Name, &req.Rules)
 	if err != nil {
-		logger.Warn("Invalid request body", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		logger.Warn("Invalid acl rules", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	AclID, err := h.groups.CreateACL(r.Context(), req.Name, aclRules)
+	if isGroupError(err) {
+		logger.Warn("Invalid acl group reference", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	AclID, err := h.acl.Create(r.Context(), req.Name, aclRules)
 	if err != nil {
 		logger.Error("Failed to create acl", zap.Error(err))
 		http.Error(w, "Failed to create acl", http.StatusInternalServerError)
@@ -55,17 +75,19 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid index acl", http.StatusBadRequest)
 		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Warn("Invalid request body", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-	}
-	aclRules, err := ConvertRulesRequestToDomain(req.Rules)
+	aclRules, err := decodeRules(r, nil, &req.Rules)
 	if err != nil {
-		logger.Warn("Invalid request body", zap.Error(err))
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		logger.Warn("Invalid acl rules", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 	id := domain.AclID(uint32(aclID))
-	err = h.acl.Update(r.Context(), id, aclRules)
+	err = h.groups.UpdateACL(r.Context(), id, aclRules)
+	if isGroupError(err) {
+		logger.Warn("Invalid acl group reference", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to update acl", zap.Error(err))
 		http.Error(w, "Failed to update acl", http.StatusInternalServerError)
@@ -83,7 +105,7 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id := domain.AclID(uint32(aclID))
-	if err := h.acl.Delete(r.Context(), id); err != nil {
+	if err := h.groups.DeleteACL(r.Context(), id); err != nil {
 		logger.Error("Failed to delete ACL", zap.Uint64("aclID", aclID), zap.Error(err))
 	} else {
 		logger.Info("ACL deleted successfully", zap.Uint64("aclID", aclID))
@@ -100,7 +122,22 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to list ACL", http.StatusInternalServerError)
 		return
 	}
-	resp = ListACLResponse{InfosToResponse(acls)}
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if _, err := io.WriteString(w, InfosToText(acls)); err != nil {
+			logger.Error("Failed to write response", zap.Error(err))
+		}
+		return
+	}
+	stats, err := h.acl.ListStats(r.Context(), acls)
+	if err != nil {
+		// the rules are still listed, only without counters
+		if !errors.Is(err, aclServ.ErrStatsUnavailable) {
+			logger.Warn("Failed to read acl counters", zap.Error(err))
+		}
+		stats = nil
+	}
+	resp = ListACLResponse{InfosToResponse(acls, stats)}
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		logger.Error("Failed to encode response", zap.Error(err))
@@ -108,3 +145,289 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// Import translates an iptables-save or nft -j list ruleset body into one
+// ACL per chain, format is iptables or nft and defaults to nft for JSON
+// bodies. With dry_run the ACLs are returned without being created. An
+// import leaving out a DROP or REJECT rule is refused unless force is set.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "iptables"
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+			format = "nft"
+		}
+	}
+	dryRun, _ := strconv.ParseBool(query.Get("dry_run"))
+	force, _ := strconv.ParseBool(query.Get("force"))
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	var result aclimport.Result
+	var err error
+	switch format {
+	case "iptables":
+		result, err = aclimport.ParseIPTablesSave(body)
+	case "nft":
+		result, err = aclimport.ParseNFTJSON(body)
+	default:
+		logger.Warn("Invalid import format", zap.String("format", format))
+		http.Error(w, "format must be iptables or nft", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Warn("Invalid ruleset", zap.String("format", format), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if dryRun {
+		response.JSON(w, ImportToResponse(result, nil))
+		return
+	}
+	if result.SkipsDeny() && !force {
+		logger.Warn("Import leaves out deny rules", zap.String("format", format), zap.Int("skipped", len(result.Issues)))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		if err := json.NewEncoder(w).Encode(ImportToResponse(result, nil)); err != nil {
+			logger.Error("Failed to encode response", zap.Error(err))
+		}
+		return
+	}
+	ids, err := h.acl.Import(r.Context(), result.Chains)
+	if err != nil {
+		logger.Error("Failed to import acls", zap.Error(err))
+		http.Error(w, "Failed to import acls", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(ImportToResponse(result, ids)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
+
+func (h *Handler) Analyze(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	aclID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid index acl in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid index acl", http.StatusBadRequest)
+		return
+	}
+
+	info, findings, err := h.acl.Analyze(r.Context(), domain.AclID(uint32(aclID)))
+	switch {
+	case errors.Is(err, aclServ.ErrNotFound):
+		logger.Warn("Failed acl not found", zap.Uint64("aclID", aclID), zap.Error(err))
+		http.Error(w, "acl not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to analyze acl", zap.Uint64("aclID", aclID), zap.Error(err))
+		http.Error(w, "Failed to analyze acl", http.StatusInternalServerError)
+	default:
+		response.JSON(w, AnalysisToResponse(info, findings))
+	}
+}
+
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	aclID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid index acl in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid index acl", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.acl.GetStats(r.Context(), domain.AclID(uint32(aclID)))
+	switch {
+	case errors.Is(err, aclServ.ErrNotFound):
+		logger.Warn("Failed acl not found", zap.Uint64("aclID", aclID), zap.Error(err))
+		http.Error(w, "acl not found", http.StatusNotFound)
+	case errors.Is(err, aclServ.ErrStatsUnavailable):
+		logger.Warn("Acl counters unavailable", zap.Error(err))
+		http.Error(w, "acl counters unavailable", http.StatusServiceUnavailable)
+	case err != nil:
+		logger.Error("Failed to get acl stats", zap.Uint64("aclID", aclID), zap.Error(err))
+		http.Error(w, "Failed to get acl stats", http.StatusInternalServerError)
+	default:
+		response.JSON(w, StatsToResponse(stats))
+	}
+}
+
+func (h *Handler) ResetStats(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	aclID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid index acl in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid index acl", http.StatusBadRequest)
+		return
+	}
+
+	err = h.acl.ResetStats(r.Context(), domain.AclID(uint32(aclID)))
+	switch {
+	case errors.Is(err, aclServ.ErrNotFound):
+		logger.Warn("Failed acl not found", zap.Uint64("aclID", aclID), zap.Error(err))
+		http.Error(w, "acl not found", http.StatusNotFound)
+	case errors.Is(err, aclServ.ErrStatsUnavailable):
+		logger.Warn("Acl counters unavailable", zap.Error(err))
+		http.Error(w, "acl counters unavailable", http.StatusServiceUnavailable)
+	case err != nil:
+		logger.Error("Failed to reset acl stats", zap.Uint64("aclID", aclID), zap.Error(err))
+		http.Error(w, "Failed to reset acl stats", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) Simulate(w http.ResponseWriter, r *http.Request) {
+	var req SimulateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	pkt, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid simulated packet", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), req.Interface)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("interface", req.Interface))
+		return
+	}
+
+	input := req.Direction == 1
+	sim, err := h.acl.Simulate(r.Context(), ifIndex, input, pkt)
+	if err != nil {
+		logger.Error("Failed to simulate acls", zap.Uint32("interface", ifIndex), zap.Error(err))
+		http.Error(w, "Failed to simulate acls", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, SimulationToResponse(ifIndex, input, sim))
+}
+
+func (h *Handler) GetSessionStats(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	stats, err := h.acl.GetSessionStats(r.Context(), ifIndex)
+	if err != nil {
+		logger.Error("Failed to get acl sessions", zap.Uint32("interface", ifIndex), zap.Error(err))
+		http.Error(w, "Failed to get acl sessions", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, SessionStatsToResponse(stats))
+}
+
+func (h *Handler) ClearSessions(w http.ResponseWriter, r *http.Request) {
+	if err := h.acl.ClearSessions(r.Context()); err != nil {
+		logger.Error("Failed to clear acl sessions", zap.Error(err))
+		http.Error(w, "Failed to clear acl sessions", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ClearInterfaceSessions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	if err := h.acl.ClearInterfaceSessions(r.Context(), ifIndex); err != nil {
+		logger.Error("Failed to clear acl sessions", zap.Uint32("interface", ifIndex), zap.Error(err))
+		http.Error(w, "Failed to clear acl sessions", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetSessionTimeouts(w http.ResponseWriter, r *http.Request) {
+	timeouts, err := h.acl.GetSessionTimeouts(r.Context())
+	switch {
+	case errors.Is(err, aclServ.ErrTimeoutsUnknown):
+		logger.Warn("Acl session timeouts unknown", zap.Error(err))
+		http.Error(w, "VPP does not report the session timeouts and none were set", http.StatusServiceUnavailable)
+	case err != nil:
+		logger.Error("Failed to get acl session timeouts", zap.Error(err))
+		http.Error(w, "Failed to get acl session timeouts", http.StatusInternalServerError)
+	default:
+		response.JSON(w, SessionTimeoutsToResponse(timeouts))
+	}
+}
+
+// SetSessionTimeouts keeps the current value of every timeout missing from
+// the body, all of them are required when the current values are unknown
+func (h *Handler) SetSessionTimeouts(w http.ResponseWriter, r *http.Request) {
+	current, err := h.acl.GetSessionTimeouts(r.Context())
+	if err != nil && !errors.Is(err, aclServ.ErrTimeoutsUnknown) {
+		logger.Error("Failed to get acl session timeouts", zap.Error(err))
+		http.Error(w, "Failed to get acl session timeouts", http.StatusInternalServerError)
+		return
+	}
+	req := SessionTimeoutsToResponse(current)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	timeouts, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid acl session timeouts", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.acl.SetSessionTimeouts(r.Context(), timeouts); err != nil {
+		logger.Error("Failed to set acl session timeouts", zap.Error(err))
+		http.Error(w, "Failed to set acl session timeouts", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, SessionTimeoutsToResponse(timeouts))
+}
+
+// decodeRules reads the rules from a JSON body, or from a text/plain body in
+// the acltext syntax with the ACL name taken from the name query parameter
+func decodeRules(r *http.Request, name *string, rules *[]RulesRequest) ([]domain.ACLGroupRule, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/plain" {
+		body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxTextBodySize))
+		if err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+		if name != nil {
+			*name = r.URL.Query().Get("name")
+		}
+		parsed, err := acltext.Parse(string(body))
+		if err != nil {
+			return nil, err
+		}
+		out := make([]domain.ACLGroupRule, len(parsed))
+		for i, rule := range parsed {
+			out[i] = domain.ACLGroupRule{ACLRule: rule}
+		}
+		return out, nil
+	}
+
+	body := struct {
+		Name  *string         `json:"name"`
+		Rules *[]RulesRequest `json:"rules"`
+	}{name, rules}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	return ConvertRulesRequestToDomain(*rules)
+}
+
+// isGroupError reports errors caused by the group references of the rules
+func isGroupError(err error) bool {
+	return errors.Is(err, aclgroup.ErrNotFound) || errors.Is(err, aclgroup.ErrInvalid)
+}
@@ -2,36 +2,55 @@ package acl
 
 import (
 	"fmt"
+	"net"
+	"strings"
 
+	"github.com/NikolayStepanov/RapidVPP/internal/aclimport"
+	"github.com/NikolayStepanov/RapidVPP/internal/acltext"
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 )
 
-func ConvertRulesRequestToDomain(rules []RulesRequest) ([]domain.ACLRule, error) {
+const (
+	protoICMP   = 1
+	protoICMPv6 = 58
+)
+
+func ConvertRulesRequestToDomain(rules []RulesRequest) ([]domain.ACLGroupRule, error) {
 	if len(rules) == 0 {
 		return nil, fmt.Errorf("rules list is empty")
 	}
 
-	out := make([]domain.ACLRule, 0, len(rules))
+	out := make([]domain.ACLGroupRule, 0, len(rules))
 
 	for i, r := range rules {
-		if r.Src.Address == "" {
+		if r.Src.Address == "" && r.SrcGroup == "" {
 			return nil, fmt.Errorf("rule %d: source address is empty", i)
 		}
-		if r.Dst.Address == "" {
+		if r.Dst.Address == "" && r.DstGroup == "" {
 			return nil, fmt.Errorf("rule %d: destination address is empty", i)
 		}
+		action := domain.ACLAction(r.Action)
+		if !action.Valid() {
+			return nil, fmt.Errorf("rule %d: invalid action %d, expected 0 (deny), 1 (permit) or 2 (reflect)", i, r.Action)
+		}
 
-		out = append(out, domain.ACLRule{
-			Action:        domain.ACLAction(r.Action), // 0=deny,1=permit
-			Proto:         r.Proto,
-			Src:           domain.IPWithPrefix{Address: r.Src.Address, Prefix: r.Src.Prefix},
-			Dst:           domain.IPWithPrefix{Address: r.Dst.Address, Prefix: r.Dst.Prefix},
-			SrcPortLow:    r.SrcPortLow,
-			SrcPortHigh:   r.SrcPortHigh,
-			DstPortLow:    r.DstPortLow,
-			DstPortHigh:   r.DstPortHigh,
-			TCPFlagsMask:  r.TCPFlagsMask,
-			TCPFlagsValue: r.TCPFlagsValue,
+		out = append(out, domain.ACLGroupRule{
+			ACLRule: domain.ACLRule{
+				Action:        action,
+				Proto:         r.Proto,
+				Src:           domain.IPWithPrefix{Address: r.Src.Address, Prefix: r.Src.Prefix},
+				Dst:           domain.IPWithPrefix{Address: r.Dst.Address, Prefix: r.Dst.Prefix},
+				SrcPortLow:    r.SrcPortLow,
+				SrcPortHigh:   r.SrcPortHigh,
+				DstPortLow:    r.DstPortLow,
+				DstPortHigh:   r.DstPortHigh,
+				TCPFlagsMask:  r.TCPFlagsMask,
+				TCPFlagsValue: r.TCPFlagsValue,
+			},
+			SrcGroup:     r.SrcGroup,
+			DstGroup:     r.DstGroup,
+			SrcPortGroup: r.SrcPortGroup,
+			DstPortGroup: r.DstPortGroup,
 		})
 	}
 
@@ -44,6 +63,7 @@ func AclInfoToResponse(info domain.ACLInfo) ACLResponse {
 	for i, r := range info.Rules {
 		rules[i] = RulesResponse{
 			Action:        uint8(r.Action),
+			ActionName:    r.Action.String(),
 			Proto:         r.Proto,
 			Src:           IPWithPrefix{Address: r.Src.Address, Prefix: r.Src.Prefix},
 			Dst:           IPWithPrefix{Address: r.Dst.Address, Prefix: r.Dst.Prefix},
@@ -63,10 +83,177 @@ func AclInfoToResponse(info domain.ACLInfo) ACLResponse {
 	}
 }
 
-func InfosToResponse(acls []domain.ACLInfo) []ACLResponse {
+// InfosToResponse adds the hit counters of stats to the rules, stats is nil
+// when the counters could not be read
+func InfosToResponse(acls []domain.ACLInfo, stats []domain.ACLStats) []ACLResponse {
 	responses := make([]ACLResponse, len(acls))
 	for i, acl := range acls {
 		responses[i] = AclInfoToResponse(acl)
+		if stats != nil {
+			addCounters(responses[i].Rules, stats[i].Rules)
+		}
 	}
 	return responses
 }
+
+func addCounters(rules []RulesResponse, counters []domain.ACLRuleCounter) {
+	for i := range rules {
+		if i < len(counters) {
+			rules[i].Hits = &counters[i].Packets
+			rules[i].Bytes = &counters[i].Bytes
+		}
+	}
+}
+
+func StatsToResponse(stats domain.ACLStats) StatsResponse {
+	rules := make([]RuleStatsResponse, len(stats.Rules))
+	for i, r := range stats.Rules {
+		rules[i] = RuleStatsResponse{
+			Rule:  i,
+			Hits:  r.Packets,
+			Bytes: r.Bytes,
+		}
+	}
+	return StatsResponse{
+		ID:    uint32(stats.ID),
+		Rules: rules,
+	}
+}
+
+// InfosToText renders every ACL as a comment line with its index and name
+// followed by its rules in the acltext syntax
+func InfosToText(acls []domain.ACLInfo) string {
+	var b strings.Builder
+	for _, acl := range acls {
+		fmt.Fprintf(&b, "# acl %d %s\n", acl.ID, acl.Name)
+		b.WriteString(acltext.FormatRules(acl.Rules))
+	}
+	return b.String()
+}
+
+// ImportToResponse pairs the chains with the ids they were created with, ids
+// is nil on a dry run
+func ImportToResponse(result aclimport.Result, ids []domain.AclID) ImportResponse {
+	resp := ImportResponse{
+		ACLs:    make([]ImportedACLResponse, len(result.Chains)),
+		Skipped: make([]ImportIssueResponse, len(result.Issues)),
+	}
+	for i, chain := range result.Chains {
+		rules := make([]string, len(chain.Rules))
+		for j, rule := range chain.Rules {
+			rules[j] = acltext.Format(rule)
+		}
+		resp.ACLs[i] = ImportedACLResponse{Name: chain.Name, Rules: rules}
+		if i < len(ids) {
+			id := uint32(ids[i])
+			resp.ACLs[i].ID = &id
+		}
+	}
+	for i, issue := range result.Issues {
+		resp.Skipped[i] = ImportIssueResponse{Chain: issue.Chain, Rule: issue.Rule, Reason: issue.Reason, Deny: issue.Deny}
+	}
+	return resp
+}
+
+func AnalysisToResponse(info domain.ACLInfo, findings []domain.ACLFinding) AnalysisResponse {
+	resp := AnalysisResponse{
+		ID:       uint32(info.ID),
+		Name:     info.Name,
+		Rules:    len(info.Rules),
+		Findings: make([]FindingResponse, len(findings)),
+	}
+	for i, f := range findings {
+		resp.Findings[i] = FindingResponse{
+			Kind:        string(f.Kind),
+			Rule:        f.Rule,
+			RuleText:    acltext.Format(info.Rules[f.Rule]),
+			Related:     f.Related,
+			RelatedText: acltext.Format(info.Rules[f.Related]),
+		}
+	}
+	return resp
+}
+
+func (req SimulateRequest) ToDomain() (domain.ACLPacket, error) {
+	src, dst := net.ParseIP(req.Src), net.ParseIP(req.Dst)
+	if src == nil {
+		return domain.ACLPacket{}, fmt.Errorf("invalid source address %q", req.Src)
+	}
+	if dst == nil {
+		return domain.ACLPacket{}, fmt.Errorf("invalid destination address %q", req.Dst)
+	}
+	if (src.To4() == nil) != (dst.To4() == nil) {
+		return domain.ACLPacket{}, fmt.Errorf("source and destination address families differ")
+	}
+	if req.Direction > 1 {
+		return domain.ACLPacket{}, fmt.Errorf("invalid direction %d, expected 0 (output) or 1 (input)", req.Direction)
+	}
+
+	pkt := domain.ACLPacket{
+		Src:      src,
+		Dst:      dst,
+		Proto:    req.Proto,
+		SrcPort:  req.SrcPort,
+		DstPort:  req.DstPort,
+		TCPFlags: req.TCPFlags,
+	}
+	if req.Proto == protoICMP || req.Proto == protoICMPv6 {
+		pkt.SrcPort, pkt.DstPort = uint16(req.ICMPType), uint16(req.ICMPCode)
+	}
+	return pkt, nil
+}
+
+func SimulationToResponse(ifIndex uint32, input bool, sim domain.ACLSimulation) SimulateResponse {
+	resp := SimulateResponse{
+		InterfaceID: ifIndex,
+		Direction:   "output",
+		Evaluated:   make([]EvaluatedACLResponse, len(sim.Evaluated)),
+		Verdict:     sim.Action.String(),
+	}
+	if input {
+		resp.Direction = "input"
+	}
+	for i, info := range sim.Evaluated {
+		resp.Evaluated[i] = EvaluatedACLResponse{ID: uint32(info.ID), Name: info.Name}
+	}
+
+	switch {
+	case sim.Matched:
+		rule := sim.Evaluated[len(sim.Evaluated)-1].Rules[sim.RuleIndex]
+		resp.Match = &MatchResponse{ACLID: uint32(sim.ACLID), Rule: sim.RuleIndex, RuleText: acltext.Format(rule)}
+		resp.Reason = "matched rule"
+	case len(sim.Evaluated) == 0:
+		resp.Reason = "no acls applied"
+	default:
+		resp.Reason = "no rule matched, implicit deny"
+	}
+	return resp
+}
+
+func SessionStatsToResponse(stats domain.ACLSessionStats) SessionStatsResponse {
+	return SessionStatsResponse{
+		InterfaceID: stats.InterfaceID,
+		Added:       stats.Added,
+		Deleted:     stats.Deleted,
+		Active:      stats.Active,
+	}
+}
+
+func SessionTimeoutsToResponse(timeouts domain.ACLSessionTimeouts) SessionTimeouts {
+	return SessionTimeouts{
+		UDPIdle:      timeouts.UDPIdle,
+		TCPIdle:      timeouts.TCPIdle,
+		TCPTransient: timeouts.TCPTransient,
+	}
+}
+
+func (t *SessionTimeouts) ToDomain() (domain.ACLSessionTimeouts, error) {
+	if t.UDPIdle == 0 || t.TCPIdle == 0 || t.TCPTransient == 0 {
+		return domain.ACLSessionTimeouts{}, fmt.Errorf("session timeouts must be positive")
+	}
+	return domain.ACLSessionTimeouts{
+		UDPIdle:      t.UDPIdle,
+		TCPIdle:      t.TCPIdle,
+		TCPTransient: t.TCPTransient,
+	}, nil
+}
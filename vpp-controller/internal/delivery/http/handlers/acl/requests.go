@@ -10,6 +10,7 @@ type UpdateRequest struct {
 }
 
 type RulesRequest struct {
+	// Action is 0 (deny), 1 (permit) or 2 (permit and reflect)
 	Action        uint8        `json:"action"`
 	Proto         uint8        `json:"proto"`
 	Src           IPWithPrefix `json:"src"`
@@ -20,9 +21,38 @@ type RulesRequest struct {
 	DstPortHigh   uint16       `json:"dst_port_high"`
 	TCPFlagsMask  uint8        `json:"tcp_flags_mask"`
 	TCPFlagsValue uint8        `json:"tcp_flags_value"`
+	// SrcGroup and DstGroup name address groups used instead of src and
+	// dst, the port groups replace the port ranges
+	SrcGroup     string `json:"src_group,omitempty"`
+	DstGroup     string `json:"dst_group,omitempty"`
+	SrcPortGroup string `json:"src_port_group,omitempty"`
+	DstPortGroup string `json:"dst_port_group,omitempty"`
 }
 
 type IPWithPrefix struct {
 	Address string `json:"address"`
 	Prefix  uint8  `json:"prefix"`
 }
+
+// SessionTimeouts is used for both the request and the response, omitted
+// request fields keep their current value
+type SessionTimeouts struct {
+	UDPIdle      uint32 `json:"udp_idle"`
+	TCPIdle      uint32 `json:"tcp_idle"`
+	TCPTransient uint32 `json:"tcp_transient"`
+}
+
+// SimulateRequest describes a packet arriving on (direction 1) or leaving
+// (direction 0) an interface, interface is a name or sw_if_index
+type SimulateRequest struct {
+	Interface string `json:"interface"`
+	Direction uint8  `json:"direction"`
+	Src       string `json:"src"`
+	Dst       string `json:"dst"`
+	Proto     uint8  `json:"proto"`
+	SrcPort   uint16 `json:"src_port"`
+	DstPort   uint16 `json:"dst_port"`
+	ICMPType  uint8  `json:"icmp_type"`
+	ICMPCode  uint8  `json:"icmp_code"`
+	TCPFlags  uint8  `json:"tcp_flags"`
+}
@@ -10,6 +10,7 @@ type ListACLResponse struct {
 }
 type RulesResponse struct {
 	Action        uint8        `json:"action"`
+	ActionName    string       `json:"action_name"`
 	Proto         uint8        `json:"proto"`
 	Src           IPWithPrefix `json:"src"`
 	Dst           IPWithPrefix `json:"dst"`
@@ -19,4 +20,82 @@ type RulesResponse struct {
 	DstPortHigh   uint16       `json:"dst_port_high"`
 	TCPFlagsMask  uint8        `json:"tcp_flags_mask"`
 	TCPFlagsValue uint8        `json:"tcp_flags_value"`
+	// Hits and Bytes are omitted when the counters could not be read
+	Hits  *uint64 `json:"hits,omitempty"`
+	Bytes *uint64 `json:"bytes,omitempty"`
+}
+
+type StatsResponse struct {
+	ID    uint32              `json:"id"`
+	Rules []RuleStatsResponse `json:"rules"`
+}
+
+// RuleStatsResponse refers to the rule by its zero-based position in the ACL
+type RuleStatsResponse struct {
+	Rule  int    `json:"rule"`
+	Hits  uint64 `json:"hits"`
+	Bytes uint64 `json:"bytes"`
+}
+
+type SessionStatsResponse struct {
+	InterfaceID uint32 `json:"interface_id"`
+	Added       uint64 `json:"added"`
+	Deleted     uint64 `json:"deleted"`
+	Active      uint64 `json:"active"`
+}
+
+type ImportResponse struct {
+	ACLs    []ImportedACLResponse `json:"acls"`
+	Skipped []ImportIssueResponse `json:"skipped"`
+}
+
+// ImportedACLResponse has no id on a dry run, rules are in the text syntax
+type ImportedACLResponse struct {
+	ID    *uint32  `json:"id,omitempty"`
+	Name  string   `json:"name"`
+	Rules []string `json:"rules"`
+}
+
+type ImportIssueResponse struct {
+	Chain  string `json:"chain"`
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+	Deny   bool   `json:"deny"`
+}
+
+type AnalysisResponse struct {
+	ID       uint32            `json:"id"`
+	Name     string            `json:"name"`
+	Rules    int               `json:"rules"`
+	Findings []FindingResponse `json:"findings"`
+}
+
+// FindingResponse refers to rules by their zero-based position in the ACL
+type FindingResponse struct {
+	Kind        string `json:"kind"`
+	Rule        int    `json:"rule"`
+	RuleText    string `json:"rule_text"`
+	Related     int    `json:"related"`
+	RelatedText string `json:"related_text"`
+}
+
+type SimulateResponse struct {
+	InterfaceID uint32                 `json:"interface_id"`
+	Direction   string                 `json:"direction"`
+	Evaluated   []EvaluatedACLResponse `json:"evaluated"`
+	Match       *MatchResponse         `json:"match"`
+	Verdict     string                 `json:"verdict"`
+	Reason      string                 `json:"reason"`
+}
+
+type EvaluatedACLResponse struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+// MatchResponse is the first matching rule, by zero-based position
+type MatchResponse struct {
+	ACLID    uint32 `json:"acl_id"`
+	Rule     int    `json:"rule"`
+	RuleText string `json:"rule_text"`
 }
@@ -0,0 +1,134 @@
+package aclgroup
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	aclgroupServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/aclgroup"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	groups service.ACLGroup
+}
+
+func NewHandler(groups service.ACLGroup) *Handler {
+	return &Handler{groups: groups}
+}
+
+func (h *Handler) ListAddressGroups(w http.ResponseWriter, r *http.Request) {
+	groups := h.groups.ListAddressGroups()
+	resp := ListAddressGroupsResponse{Groups: make([]AddressGroupResponse, 0, len(groups))}
+	for _, group := range groups {
+		_, acls, err := h.groups.GetAddressGroup(group.Name)
+		if err != nil {
+			continue
+		}
+		resp.Groups = append(resp.Groups, AddressGroupToResponse(group, acls))
+	}
+	response.JSON(w, resp)
+}
+
+func (h *Handler) GetAddressGroup(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	group, acls, err := h.groups.GetAddressGroup(name)
+	if err != nil {
+		writeServiceError(w, "Failed to get address group", err)
+		return
+	}
+	response.JSON(w, AddressGroupToResponse(group, acls))
+}
+
+func (h *Handler) SetAddressGroup(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var req AddressGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.groups.SetAddressGroup(r.Context(), req.ToDomain(name))
+	if err != nil {
+		writeServiceError(w, "Failed to set address group", err)
+		return
+	}
+	response.JSON(w, SetGroupResponse{Name: name, UpdatedACLs: aclIDsToResponse(updated)})
+}
+
+func (h *Handler) DeleteAddressGroup(w http.ResponseWriter, r *http.Request) {
+	if err := h.groups.DeleteAddressGroup(r.PathValue("name")); err != nil {
+		writeServiceError(w, "Failed to delete address group", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListPortGroups(w http.ResponseWriter, r *http.Request) {
+	groups := h.groups.ListPortGroups()
+	resp := ListPortGroupsResponse{Groups: make([]PortGroupResponse, 0, len(groups))}
+	for _, group := range groups {
+		_, acls, err := h.groups.GetPortGroup(group.Name)
+		if err != nil {
+			continue
+		}
+		resp.Groups = append(resp.Groups, PortGroupToResponse(group, acls))
+	}
+	response.JSON(w, resp)
+}
+
+func (h *Handler) GetPortGroup(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	group, acls, err := h.groups.GetPortGroup(name)
+	if err != nil {
+		writeServiceError(w, "Failed to get port group", err)
+		return
+	}
+	response.JSON(w, PortGroupToResponse(group, acls))
+}
+
+func (h *Handler) SetPortGroup(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var req PortGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.groups.SetPortGroup(r.Context(), req.ToDomain(name))
+	if err != nil {
+		writeServiceError(w, "Failed to set port group", err)
+		return
+	}
+	response.JSON(w, SetGroupResponse{Name: name, UpdatedACLs: aclIDsToResponse(updated)})
+}
+
+func (h *Handler) DeletePortGroup(w http.ResponseWriter, r *http.Request) {
+	if err := h.groups.DeletePortGroup(r.PathValue("name")); err != nil {
+		writeServiceError(w, "Failed to delete port group", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, aclgroupServ.ErrInvalid):
+		logger.Warn(msg, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, aclgroupServ.ErrNotFound):
+		logger.Warn(msg, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, aclgroupServ.ErrInUse), errors.Is(err, aclgroupServ.ErrChanged):
+		logger.Warn(msg, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Error(msg, zap.Error(err))
+		http.Error(w, msg, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,49 @@
+package aclgroup
+
+import (
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+func (req AddressGroupRequest) ToDomain(name string) domain.ACLAddressGroup {
+	group := domain.ACLAddressGroup{Name: name, Prefixes: make([]domain.IPWithPrefix, len(req.Prefixes))}
+	for i, p := range req.Prefixes {
+		group.Prefixes[i] = domain.IPWithPrefix{Address: p.Address, Prefix: p.Prefix}
+	}
+	return group
+}
+
+func (req PortGroupRequest) ToDomain(name string) domain.ACLPortGroup {
+	group := domain.ACLPortGroup{Name: name, Ports: make([]domain.PortRange, len(req.Ports))}
+	for i, p := range req.Ports {
+		high := p.High
+		if high == 0 {
+			high = p.Low
+		}
+		group.Ports[i] = domain.PortRange{Low: p.Low, High: high}
+	}
+	return group
+}
+
+func AddressGroupToResponse(group domain.ACLAddressGroup, acls []domain.AclID) AddressGroupResponse {
+	prefixes := make([]IPWithPrefix, len(group.Prefixes))
+	for i, p := range group.Prefixes {
+		prefixes[i] = IPWithPrefix{Address: p.Address, Prefix: p.Prefix}
+	}
+	return AddressGroupResponse{Name: group.Name, Prefixes: prefixes, ACLs: aclIDsToResponse(acls)}
+}
+
+func PortGroupToResponse(group domain.ACLPortGroup, acls []domain.AclID) PortGroupResponse {
+	ports := make([]PortRange, len(group.Ports))
+	for i, p := range group.Ports {
+		ports[i] = PortRange{Low: p.Low, High: p.High}
+	}
+	return PortGroupResponse{Name: group.Name, Ports: ports, ACLs: aclIDsToResponse(acls)}
+}
+
+func aclIDsToResponse(ids []domain.AclID) []uint32 {
+	out := make([]uint32, len(ids))
+	for i, id := range ids {
+		out[i] = uint32(id)
+	}
+	return out
+}
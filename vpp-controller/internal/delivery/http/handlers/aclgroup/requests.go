@@ -0,0 +1,20 @@
+package aclgroup
+
+type AddressGroupRequest struct {
+	Prefixes []IPWithPrefix `json:"prefixes"`
+}
+
+type PortGroupRequest struct {
+	Ports []PortRange `json:"ports"`
+}
+
+type IPWithPrefix struct {
+	Address string `json:"address"`
+	Prefix  uint8  `json:"prefix"`
+}
+
+// PortRange matches a single port when High is omitted
+type PortRange struct {
+	Low  uint16 `json:"low"`
+	High uint16 `json:"high,omitempty"`
+}
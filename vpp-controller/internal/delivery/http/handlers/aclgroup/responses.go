@@ -0,0 +1,28 @@
+package aclgroup
+
+type AddressGroupResponse struct {
+	Name     string         `json:"name"`
+	Prefixes []IPWithPrefix `json:"prefixes"`
+	// ACLs are the indexes of the ACLs referencing the group
+	ACLs []uint32 `json:"acls"`
+}
+
+type ListAddressGroupsResponse struct {
+	Groups []AddressGroupResponse `json:"groups"`
+}
+
+type PortGroupResponse struct {
+	Name  string      `json:"name"`
+	Ports []PortRange `json:"ports"`
+	ACLs  []uint32    `json:"acls"`
+}
+
+type ListPortGroupsResponse struct {
+	Groups []PortGroupResponse `json:"groups"`
+}
+
+// SetGroupResponse lists the ACLs updated with the new group members
+type SetGroupResponse struct {
+	Name        string   `json:"name"`
+	UpdatedACLs []uint32 `json:"updated_acls"`
+}
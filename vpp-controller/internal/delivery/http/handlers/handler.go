@@ -3,9 +3,17 @@ package handlers
 import (
 	"net/http"
 
-	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/Interfaces"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/abf"
 	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/acl"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/aclgroup"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/interfaces"
 	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/ip"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/ip6nd"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/macip"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/mpls"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/mroute"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/neighbor"
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/srv6"
 	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/vpp"
 	"github.com/NikolayStepanov/RapidVPP/internal/service"
 )
@@ -16,15 +24,31 @@ type Handler struct {
 	interfaceHandler *interfaces.Handler
 	ipHandler        *ip.Handler
 	aclHandler       *acl.Handler
+	neighborHandler  *neighbor.Handler
+	ip6ndHandler     *ip6nd.Handler
+	mrouteHandler    *mroute.Handler
+	mplsHandler      *mpls.Handler
+	srv6Handler      *srv6.Handler
+	abfHandler       *abf.Handler
+	macipHandler     *macip.Handler
+	aclGroupHandler  *aclgroup.Handler
 }
 
-func NewHandler(info service.Info, inter service.Interface, IPServ service.IP, aclSer service.ACL) *Handler {
+func NewHandler(info service.Info, inter service.Interface, IPServ service.IP, aclSer service.ACL, neighborSer service.Neighbor, ip6ndSer service.IP6ND, mrouteSer service.MRoute, mplsSer service.MPLS, srv6Ser service.SRv6, abfSer service.ABF, macipSer service.MACIP, aclGroupSer service.ACLGroup) *Handler {
 	handler := &Handler{
 		router:           http.NewServeMux(),
 		vppHandler:       vpp.NewHandler(info),
 		interfaceHandler: interfaces.NewHandler(inter),
-		ipHandler:        ip.NewHandler(IPServ),
-		aclHandler:       acl.NewHandler(aclSer),
+		ipHandler:        ip.NewHandler(IPServ, inter),
+		aclHandler:       acl.NewHandler(aclSer, aclGroupSer, inter),
+		neighborHandler:  neighbor.NewHandler(neighborSer, inter),
+		ip6ndHandler:     ip6nd.NewHandler(ip6ndSer, inter),
+		mrouteHandler:    mroute.NewHandler(mrouteSer, inter),
+		mplsHandler:      mpls.NewHandler(mplsSer, inter),
+		srv6Handler:      srv6.NewHandler(srv6Ser, inter),
+		abfHandler:       abf.NewHandler(abfSer, inter),
+		macipHandler:     macip.NewHandler(macipSer, inter),
+		aclGroupHandler:  aclgroup.NewHandler(aclGroupSer),
 	}
 
 	handler.setupRoutes()
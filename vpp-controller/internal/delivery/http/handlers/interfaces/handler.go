@@ -3,9 +3,10 @@ package interfaces
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 	"github.com/NikolayStepanov/RapidVPP/internal/service"
 	"github.com/NikolayStepanov/RapidVPP/internal/service/vpp/interfaces"
@@ -22,14 +23,42 @@ func NewHandler(inter service.Interface) *Handler {
 }
 
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseListFilter(r.URL.Query())
+	if err != nil {
+		logger.Warn("Invalid interface filter", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	interfaces, err := h.inter.List(r.Context())
 	if err != nil {
 		logger.Error("Failed to get list interfaces", zap.Error(err))
 		http.Error(w, "Failed to get list interfaces", http.StatusBadRequest)
 		return
 	}
+
+	urpfByInterface := make(map[uint32][]domain.URPF)
+	urpfSettings, err := h.inter.ListURPF(r.Context(), 0xFFFFFFFF)
+	if err != nil {
+		// the urpf plugin may be disabled, interfaces are listed without the setting
+		logger.Warn("Failed to get urpf settings", zap.Error(err))
+	}
+	for _, u := range urpfSettings {
+		urpfByInterface[u.InterfaceID] = append(urpfByInterface[u.InterfaceID], u)
+	}
+
+	response := make([]InterfaceResponse, 0, len(interfaces))
+	for _, details := range interfaces {
+		ifIndex := uint32(details.SwIfIndex)
+		metadata, _ := h.inter.GetMetadata(ifIndex)
+		if !filter.Match(details.Tag, metadata) {
+			continue
+		}
+		response = append(response, InterfaceToResponse(details, metadata, urpfByInterface[ifIndex]))
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(interfaces); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		logger.Error("Failed to encode response", zap.Error(err))
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
@@ -55,10 +84,9 @@ func (h *Handler) CreateLoopback(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) SetInterfaceState(w http.ResponseWriter, r *http.Request) {
 	var req SetInterfaceStateRequests
 	idStr := r.PathValue("id")
-	ifIndex, err := strconv.ParseUint(idStr, 10, 32)
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
 	if err != nil {
-		logger.Warn("Invalid index interface in request", zap.String("id", idStr), zap.Error(err))
-		http.Error(w, "Invalid index interface", http.StatusBadRequest)
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
 		return
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -66,7 +94,7 @@ func (h *Handler) SetInterfaceState(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 	}
 
-	err = h.inter.SetInterfaceAdminState(r.Context(), uint32(ifIndex), req.AdminUp)
+	err = h.inter.SetInterfaceAdminState(r.Context(), ifIndex, req.AdminUp)
 	if err != nil {
 		logger.Error("Failed to set interface state", zap.Error(err))
 		http.Error(w, "Failed to set interface state", http.StatusInternalServerError)
@@ -78,17 +106,16 @@ func (h *Handler) SetInterfaceState(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) DeleteLoopback(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
-	ifIndex, err := strconv.ParseUint(idStr, 10, 32)
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
 	if err != nil {
-		logger.Warn("Invalid index interface in request", zap.String("id", idStr), zap.Error(err))
-		http.Error(w, "Invalid index interface", http.StatusBadRequest)
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
 		return
 	}
 
-	if err := h.inter.DeleteLoopback(r.Context(), uint32(ifIndex)); err != nil {
-		logger.Error("Failed to delete loopback interface", zap.Uint64("ifIndex", ifIndex), zap.Error(err))
+	if err := h.inter.DeleteLoopback(r.Context(), ifIndex); err != nil {
+		logger.Error("Failed to delete loopback interface", zap.Uint32("ifIndex", ifIndex), zap.Error(err))
 	} else {
-		logger.Info("Loopback interface deleted successfully", zap.Uint64("ifIndex", ifIndex))
+		logger.Info("Loopback interface deleted successfully", zap.Uint32("ifIndex", ifIndex))
 	}
 
 	w.WriteHeader(http.StatusNoContent)
@@ -97,18 +124,17 @@ func (h *Handler) DeleteLoopback(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) AddInterfaceIP(w http.ResponseWriter, r *http.Request) {
 	var req AddIPRequest
 	idStr := r.PathValue("id")
-	ifIndex, err := strconv.ParseUint(idStr, 10, 32)
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
 	if err != nil {
-		logger.Warn("Invalid index interface in request", zap.String("id", idStr), zap.Error(err))
-		http.Error(w, "Invalid index interface", http.StatusBadRequest)
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
 		return
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		logger.Warn("Invalid request body", zap.Error(err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 	}
-	IPWithPrefix := domain.IPWithPrefix{req.Address, req.Prefix}
-	err = h.inter.SetInterfaceIP(r.Context(), uint32(ifIndex), IPWithPrefix)
+	IPWithPrefix := domain.IPWithPrefix{Address: req.Address, Prefix: req.Prefix}
+	err = h.inter.SetInterfaceIP(r.Context(), ifIndex, IPWithPrefix)
 	switch {
 	case errors.Is(err, interfaces.ErrNotFound):
 		logger.Error("Failed interface not found", zap.Error(err))
@@ -132,18 +158,28 @@ func (h *Handler) AddInterfaceIP(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) AttachACL(w http.ResponseWriter, r *http.Request) {
 	var req AttachACLRequest
 	idStr := r.PathValue("id")
-	ifIndex, err := strconv.ParseUint(idStr, 10, 32)
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
 	if err != nil {
-		logger.Warn("Invalid index interface in request", zap.String("id", idStr), zap.Error(err))
-		http.Error(w, "Invalid index interface", http.StatusBadRequest)
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
 		return
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		logger.Warn("Invalid request body", zap.Error(err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
-	err = h.inter.AttachACL(r.Context(), uint32(ifIndex), req.AclId, req.Direction)
+	if req.Position != nil {
+		aclList, err := h.inter.InsertACL(r.Context(), ifIndex, req.AclId, req.Direction, *req.Position)
+		if err != nil {
+			writeACLListError(w, err)
+			return
+		}
+		writeACLList(w, aclList)
+		return
+	}
+
+	err = h.inter.AttachACL(r.Context(), ifIndex, req.AclId, req.Direction)
 	if err != nil {
 		logger.Error("Failed to attach ACL interface", zap.Error(err))
 		http.Error(w, "Failed to attach ACL interface", http.StatusInternalServerError)
@@ -157,19 +193,19 @@ func (h *Handler) DetachACL(w http.ResponseWriter, r *http.Request) {
 	var req DetachACLRequest
 
 	idStr := r.PathValue("id")
-	ifIndex, err := strconv.ParseUint(idStr, 10, 32)
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
 	if err != nil {
-		logger.Warn("Invalid index interface in request", zap.String("id", idStr), zap.Error(err))
-		http.Error(w, "Invalid index interface", http.StatusBadRequest)
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
 		return
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		logger.Warn("Invalid request body", zap.Error(err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
-	err = h.inter.DetachACL(r.Context(), uint32(ifIndex), req.AclId, req.Direction)
+	err = h.inter.DetachACL(r.Context(), ifIndex, req.AclId, req.Direction)
 	if err != nil {
 		logger.Error("Failed to detach ACL interface", zap.Error(err))
 		http.Error(w, "Failed to detach ACL interface", http.StatusInternalServerError)
@@ -181,24 +217,306 @@ func (h *Handler) DetachACL(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) ListACL(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
-	ifIndex, err := strconv.ParseUint(idStr, 10, 32)
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
 	if err != nil {
-		logger.Warn("Invalid index interface in request", zap.String("id", idStr), zap.Error(err))
-		http.Error(w, "Invalid index interface", http.StatusBadRequest)
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
 		return
 	}
 
-	aclList, err := h.inter.ListACL(r.Context(), uint32(ifIndex))
+	aclList, err := h.inter.ListACL(r.Context(), ifIndex)
 	if err != nil {
 		logger.Error("Failed to get list acl interface", zap.Error(err))
 		http.Error(w, "Failed to get list acl interface", http.StatusBadRequest)
 		return
 	}
-	aclListDTO := ACLInterfaceListToDTO(aclList)
+	writeACLList(w, aclList)
+}
+
+func (h *Handler) SetACLList(w http.ResponseWriter, r *http.Request) {
+	var req SetACLListRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	aclList, err := h.inter.SetACLList(r.Context(), ifIndex, req.Input, req.Output)
+	if err != nil {
+		writeACLListError(w, err)
+		return
+	}
+	writeACLList(w, aclList)
+}
+
+func writeACLList(w http.ResponseWriter, aclList domain.ACLInterfaceList) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ACLInterfaceListToDTO(aclList)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func writeACLListError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, interfaces.ErrInvalidACL):
+		logger.Warn("Invalid interface acl list", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, interfaces.ErrACLNotFound):
+		logger.Error("Failed acl not found", zap.Error(err))
+		http.Error(w, "acl not found", http.StatusNotFound)
+	case errors.Is(err, interfaces.ErrNotFound):
+		logger.Error("Failed interface not found", zap.Error(err))
+		http.Error(w, "interface not found", http.StatusNotFound)
+	default:
+		logger.Error("Failed to set interface acl list", zap.Error(err))
+		http.Error(w, "Failed to set interface acl list", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) SetUnnumbered(w http.ResponseWriter, r *http.Request) {
+	var req SetUnnumberedRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ipIfIndex := req.IPInterfaceID
+	if req.IPInterface != "" {
+		ipIfIndex, err = h.inter.ResolveInterface(r.Context(), req.IPInterface)
+		if err != nil {
+			response.ResolveError(w, "Invalid ip interface", err, zap.String("ip_interface", req.IPInterface))
+			return
+		}
+	}
+
+	err = h.inter.SetUnnumbered(r.Context(), ifIndex, ipIfIndex)
+	switch {
+	case errors.Is(err, interfaces.ErrInvalid):
+		logger.Warn("Invalid unnumbered interface", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, interfaces.ErrNotFound):
+		logger.Error("Failed interface not found", zap.Error(err))
+		http.Error(w, "interface not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to set interface unnumbered", zap.Error(err))
+		http.Error(w, "Failed to set interface unnumbered", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) DeleteUnnumbered(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	err = h.inter.DeleteUnnumbered(r.Context(), ifIndex)
+	switch {
+	case errors.Is(err, interfaces.ErrNotFound):
+		logger.Error("Failed unnumbered interface not found", zap.Error(err))
+		http.Error(w, "unnumbered interface not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to delete interface unnumbered", zap.Error(err))
+		http.Error(w, "Failed to delete interface unnumbered", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) ListUnnumbered(w http.ResponseWriter, r *http.Request) {
+	unnumbered, err := h.inter.ListUnnumbered(r.Context())
+	if err != nil {
+		logger.Error("Failed to get list unnumbered interfaces", zap.Error(err))
+		http.Error(w, "Failed to get list unnumbered interfaces", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(UnnumberedToDTO(unnumbered)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SetTag(w http.ResponseWriter, r *http.Request) {
+	var req SetTagRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Tag) > interfaces.MaxTagLength {
+		logger.Warn("Invalid tag in request", zap.Int("length", len(req.Tag)))
+		http.Error(w, fmt.Sprintf("tag exceeds %d characters", interfaces.MaxTagLength), http.StatusBadRequest)
+		return
+	}
+
+	err = h.inter.SetTag(r.Context(), ifIndex, req.Tag)
+	switch {
+	case errors.Is(err, interfaces.ErrInvalid):
+		logger.Warn("Invalid tag", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, interfaces.ErrNotFound):
+		logger.Error("Failed interface not found", zap.Error(err))
+		http.Error(w, "interface not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to set interface tag", zap.Error(err))
+		http.Error(w, "Failed to set interface tag", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) GetMetadata(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	metadata, ok := h.inter.GetMetadata(ifIndex)
+	if !ok {
+		http.Error(w, "interface metadata not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(MetadataToDTO(metadata)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SetMetadata(w http.ResponseWriter, r *http.Request) {
+	var req SetMetadataRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.inter.SetMetadata(r.Context(), ifIndex, req.ToDomain())
+	switch {
+	case errors.Is(err, interfaces.ErrNotFound):
+		logger.Error("Failed interface not found", zap.Error(err))
+		http.Error(w, "interface not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to set interface metadata", zap.Error(err))
+		http.Error(w, "Failed to set interface metadata", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) DeleteMetadata(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	if err := h.inter.DeleteMetadata(ifIndex); err != nil {
+		logger.Warn("Failed to delete interface metadata", zap.Uint32("ifIndex", ifIndex), zap.Error(err))
+		http.Error(w, "interface metadata not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetURPF(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	settings, err := h.inter.ListURPF(r.Context(), ifIndex)
+	switch {
+	case errors.Is(err, interfaces.ErrNotFound):
+		logger.Warn("Interface not found", zap.Uint32("interface", ifIndex))
+		http.Error(w, "interface not found", http.StatusNotFound)
+		return
+	case err != nil:
+		logger.Error("Failed to get urpf settings", zap.Error(err))
+		http.Error(w, "Failed to get urpf settings", http.StatusInternalServerError)
+		return
+	}
+
+	response := URPFToDTO(settings)
+	if response == nil {
+		response = []URPFResponse{}
+	}
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(aclListDTO); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		logger.Error("Failed to encode response", zap.Error(err))
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
+
+func (h *Handler) SetURPF(w http.ResponseWriter, r *http.Request) {
+	var req SetURPFRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.inter.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	setting, err := req.ToDomain(ifIndex)
+	if err != nil {
+		logger.Warn("Invalid urpf setting", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.inter.SetURPF(r.Context(), setting)
+	switch {
+	case errors.Is(err, interfaces.ErrNotFound):
+		logger.Warn("Interface not found", zap.Uint32("interface", ifIndex))
+		http.Error(w, "interface not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to set urpf", zap.Error(err))
+		http.Error(w, "Failed to set urpf", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
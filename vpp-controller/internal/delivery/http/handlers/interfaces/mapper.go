@@ -1,6 +1,14 @@
 package interfaces
 
-import "github.com/NikolayStepanov/RapidVPP/internal/domain"
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	interfaces "go.fd.io/govpp/binapi/interface"
+)
 
 func ACLInterfaceListToDTO(aclInterfaceList domain.ACLInterfaceList) ACLInterfaceListResponses {
 	return ACLInterfaceListResponses{
@@ -10,3 +18,134 @@ func ACLInterfaceListToDTO(aclInterfaceList domain.ACLInterfaceList) ACLInterfac
 		OutputACLs:  aclInterfaceList.OutputACLs,
 	}
 }
+
+func UnnumberedToDTO(unnumbered []domain.UnnumberedInterface) []UnnumberedResponse {
+	res := make([]UnnumberedResponse, 0, len(unnumbered))
+	for _, u := range unnumbered {
+		res = append(res, UnnumberedResponse{
+			InterfaceID:   u.InterfaceID,
+			IPInterfaceID: u.IPInterfaceID,
+		})
+	}
+	return res
+}
+
+func InterfaceToResponse(details interfaces.SwInterfaceDetails, metadata domain.InterfaceMetadata, urpf []domain.URPF) InterfaceResponse {
+	return InterfaceResponse{
+		SwInterfaceDetails: details,
+		Description:        metadata.Description,
+		Owner:              metadata.Owner,
+		Labels:             metadata.Labels,
+		URPF:               URPFToDTO(urpf),
+	}
+}
+
+func URPFToDTO(settings []domain.URPF) []URPFResponse {
+	if len(settings) == 0 {
+		return nil
+	}
+	res := make([]URPFResponse, 0, len(settings))
+	for _, u := range settings {
+		af, direction := afIPv4, directionIngress
+		if u.IPv6 {
+			af = afIPv6
+		}
+		if u.Egress {
+			direction = directionEgress
+		}
+		res = append(res, URPFResponse{
+			AF:        af,
+			Direction: direction,
+			Mode:      u.Mode.String(),
+			TableID:   u.TableID,
+		})
+	}
+	return res
+}
+
+const (
+	afIPv4           = "ip4"
+	afIPv6           = "ip6"
+	directionIngress = "ingress"
+	directionEgress  = "egress"
+)
+
+func (r *SetURPFRequest) ToDomain(ifIndex uint32) (domain.URPF, error) {
+	setting := domain.URPF{
+		InterfaceID: ifIndex,
+		TableID:     0xFFFFFFFF,
+	}
+
+	switch r.AF {
+	case afIPv4, "ipv4":
+	case afIPv6, "ipv6":
+		setting.IPv6 = true
+	default:
+		return domain.URPF{}, fmt.Errorf("invalid af %q. Use ip4 or ip6", r.AF)
+	}
+
+	switch r.Direction {
+	case "", directionIngress:
+	case directionEgress:
+		setting.Egress = true
+	default:
+		return domain.URPF{}, fmt.Errorf("invalid direction %q. Use ingress or egress", r.Direction)
+	}
+
+	mode, err := domain.ParseURPFMode(r.Mode)
+	if err != nil {
+		return domain.URPF{}, err
+	}
+	setting.Mode = mode
+
+	if r.TableID != nil {
+		setting.TableID = *r.TableID
+	}
+	return setting, nil
+}
+
+func MetadataToDTO(metadata domain.InterfaceMetadata) MetadataResponse {
+	return MetadataResponse{
+		Description: metadata.Description,
+		Owner:       metadata.Owner,
+		Labels:      metadata.Labels,
+	}
+}
+
+func (r *SetMetadataRequest) ToDomain() domain.InterfaceMetadata {
+	return domain.InterfaceMetadata{
+		Description: r.Description,
+		Owner:       r.Owner,
+		Labels:      maps.Clone(r.Labels),
+	}
+}
+
+// ParseListFilter reads tag, owner and label query parameters,
+// labels are given as key=value or key and may be repeated
+func ParseListFilter(query url.Values) (ListFilter, error) {
+	filter := ListFilter{
+		Tag:    query.Get("tag"),
+		Owner:  query.Get("owner"),
+		Labels: make(map[string]string),
+	}
+
+	for _, label := range query["label"] {
+		key, value, _ := strings.Cut(label, "=")
+		if key == "" {
+			return ListFilter{}, fmt.Errorf("invalid label selector: %q", label)
+		}
+		filter.Labels[key] = value
+	}
+
+	return filter, nil
+}
+
+func (f ListFilter) Match(tag string, metadata domain.InterfaceMetadata) bool {
+	if f.Tag != "" && f.Tag != tag {
+		return false
+	}
+	if f.Owner != "" && f.Owner != metadata.Owner {
+		return false
+	}
+	return metadata.MatchLabels(f.Labels)
+}
@@ -12,9 +12,48 @@ type AddIPRequest struct {
 type AttachACLRequest struct {
 	AclId     uint32 `json:"acl_id"`
 	Direction uint8  `json:"direction"`
+	// Position inserts the ACL at the given index instead of appending it
+	Position *int `json:"position,omitempty"`
+}
+
+type SetACLListRequest struct {
+	Input  []uint32 `json:"input"`
+	Output []uint32 `json:"output"`
 }
 
 type DetachACLRequest struct {
 	AclId     uint32 `json:"acl_id"`
 	Direction uint8  `json:"direction"`
 }
+
+type SetUnnumberedRequest struct {
+	IPInterfaceID uint32 `json:"ip_interface_id"`
+	IPInterface   string `json:"ip_interface,omitempty"`
+}
+
+type SetTagRequest struct {
+	Tag string `json:"tag"`
+}
+
+type SetMetadataRequest struct {
+	Description string            `json:"description"`
+	Owner       string            `json:"owner"`
+	Labels      map[string]string `json:"labels"`
+}
+
+type ListFilter struct {
+	Tag    string
+	Owner  string
+	Labels map[string]string
+}
+
+type SetURPFRequest struct {
+	// AF is ip4 or ip6
+	AF string `json:"af"`
+	// Direction is ingress (default) or egress
+	Direction string `json:"direction,omitempty"`
+	// Mode is off, loose or strict
+	Mode string `json:"mode"`
+	// TableID is the table the source is looked up in, the interface's table by default
+	TableID *uint32 `json:"table_id,omitempty"`
+}
@@ -1,8 +1,36 @@
 package interfaces
 
+import interfaces "go.fd.io/govpp/binapi/interface"
+
+type InterfaceResponse struct {
+	interfaces.SwInterfaceDetails
+	Description string            `json:"description,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	URPF        []URPFResponse    `json:"urpf,omitempty"`
+}
+
 type ACLInterfaceListResponses struct {
 	InterfaceID uint32   `json:"interface_id"`
 	Count       uint8    `json:"count"`
 	InputACLs   []uint32 `json:"input_acls"`
 	OutputACLs  []uint32 `json:"output_acls"`
 }
+
+type UnnumberedResponse struct {
+	InterfaceID   uint32 `json:"interface_id"`
+	IPInterfaceID uint32 `json:"ip_interface_id"`
+}
+
+type MetadataResponse struct {
+	Description string            `json:"description"`
+	Owner       string            `json:"owner"`
+	Labels      map[string]string `json:"labels"`
+}
+
+type URPFResponse struct {
+	AF        string `json:"af"`
+	Direction string `json:"direction"`
+	Mode      string `json:"mode"`
+	TableID   uint32 `json:"table_id"`
+}
@@ -1,23 +1,29 @@
 package ip
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"strconv"
 
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	ipServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/ip"
 	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	ip service.IP
+	ip       service.IP
+	resolver service.InterfaceResolver
 }
 
-func NewHandler(ip service.IP) *Handler {
-	return &Handler{ip: ip}
+func NewHandler(ip service.IP, resolver service.InterfaceResolver) *Handler {
+	return &Handler{ip: ip, resolver: resolver}
 }
 
 func (h *Handler) AddRoute(w http.ResponseWriter, r *http.Request) {
@@ -27,6 +33,10 @@ func (h *Handler) AddRoute(w http.ResponseWriter, r *http.Request) {
 		logger.Warn("Invalid request body", zap.Error(err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 	}
+	if err := ResolveNextHops(r.Context(), h.resolver, req.NextHops); err != nil {
+		response.ResolveError(w, "Invalid next-hop interface", err)
+		return
+	}
 	route, err := req.ToDomain()
 	if err != nil {
 		logger.Error("Failed to add route", zap.Error(err))
@@ -49,6 +59,10 @@ func (h *Handler) DeleteRoute(w http.ResponseWriter, r *http.Request) {
 		logger.Warn("Invalid request body", zap.Error(err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 	}
+	if err := ResolveNextHops(r.Context(), h.resolver, req.NextHops); err != nil {
+		response.ResolveError(w, "Invalid next-hop interface", err)
+		return
+	}
 	route, err := req.ToDomain()
 	if err != nil {
 		logger.Error("Failed to delete route", zap.Error(err))
@@ -64,8 +78,115 @@ func (h *Handler) DeleteRoute(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *Handler) ReplaceRoute(w http.ResponseWriter, r *http.Request) {
+	var req AddRouteRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := ResolveNextHops(r.Context(), h.resolver, req.NextHops); err != nil {
+		response.ResolveError(w, "Invalid next-hop interface", err)
+		return
+	}
+	route, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid route", zap.Error(err))
+		http.Error(w, "Invalid route", http.StatusBadRequest)
+		return
+	}
+	if len(route.NextHops) == 0 {
+		logger.Warn("Route without next hops", zap.String("destination", req.Destination))
+		http.Error(w, "At least one next hop is required", http.StatusBadRequest)
+		return
+	}
+	if err := h.ip.ReplaceRoute(r.Context(), route); err != nil {
+		logger.Error("Failed to replace route", zap.Error(err))
+		http.Error(w, "Failed to replace route", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) PatchRoute(w http.ResponseWriter, r *http.Request) {
+	var req PatchRouteRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := ResolveNextHops(r.Context(), h.resolver, req.Add); err != nil {
+		response.ResolveError(w, "Invalid next-hop interface", err)
+		return
+	}
+	if err := ResolveNextHops(r.Context(), h.resolver, req.Remove); err != nil {
+		response.ResolveError(w, "Invalid next-hop interface", err)
+		return
+	}
+	dst, add, remove, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid route patch", zap.Error(err))
+		http.Error(w, "Invalid route patch", http.StatusBadRequest)
+		return
+	}
+
+	route, err := h.ip.ModifyRoutePaths(r.Context(), dst, req.VRF, add, remove)
+	if err != nil {
+		logger.Error("Failed to modify route paths",
+			zap.String("destination", req.Destination),
+			zap.Uint32("vrf", req.VRF),
+			zap.Error(err))
+		http.Error(w, "Failed to modify route paths", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(route); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DeleteRoutePrefix(w http.ResponseWriter, r *http.Request) {
+	vrfStr := r.PathValue("vrf")
+	vrfInt, err := strconv.ParseUint(vrfStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid VRF parameter", zap.String("vrf", vrfStr), zap.Error(err))
+		http.Error(w, "Invalid VRF ID", http.StatusBadRequest)
+		return
+	}
+	vrf := uint32(vrfInt)
+
+	prefixStr := r.URL.Query().Get("prefix")
+	ip, netw, err := net.ParseCIDR(prefixStr)
+	if err != nil {
+		logger.Warn("Invalid prefix query param", zap.String("prefix", prefixStr), zap.Error(err))
+		http.Error(w, "Invalid prefix format. Use: address/prefix", http.StatusBadRequest)
+		return
+	}
+	ones, _ := netw.Mask.Size()
+	dst := domain.IPWithPrefix{
+		Address: ip.String(),
+		Prefix:  uint8(ones),
+	}
+
+	if err := h.ip.DeleteRoutePrefix(r.Context(), dst, vrf); err != nil {
+		logger.Error("Failed to delete route prefix",
+			zap.String("prefix", prefixStr),
+			zap.Uint32("vrf", vrf),
+			zap.Error(err))
+		http.Error(w, "Failed to delete route prefix", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	vrfStr := r.URL.Query().Get("vrf")
+	query := r.URL.Query()
+	vrfStr := query.Get("vrf")
 	var vrf uint32 = 0
 
 	if vrfStr != "" {
@@ -77,12 +198,55 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 		}
 		vrf = uint32(vrfInt)
 	}
-	routes, err := h.ip.ListRoutes(r.Context(), vrf)
+
+	filter, err := ParseRouteFilter(query)
+	if err != nil {
+		logger.Warn("Invalid route filter", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ifStr := query.Get("interface"); ifStr != "" {
+		ifIndex, err := h.resolver.ResolveInterface(r.Context(), ifStr)
+		if err != nil {
+			response.ResolveError(w, "Invalid interface parameter", err, zap.String("interface", ifStr))
+			return
+		}
+		filter.InterfaceID = &ifIndex
+	}
+
+	limitStr := query.Get("limit")
+	if limitStr == "" {
+		h.streamRoutes(w, r, vrf, filter)
+		return
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		logger.Warn("Invalid limit parameter", zap.String("limit", limitStr))
+		http.Error(w, fmt.Sprintf("Invalid limit parameter. Must be between 1 and %d", maxPageLimit), http.StatusBadRequest)
+		return
+	}
+	after, err := DecodeRouteCursor(query.Get("cursor"))
+	if err != nil {
+		logger.Warn("Invalid cursor parameter", zap.Error(err))
+		http.Error(w, "Invalid cursor parameter", http.StatusBadRequest)
+		return
+	}
+
+	routes, more, err := h.ip.ListRoutesPage(r.Context(), vrf, filter, after, limit)
 	if err != nil {
 		logger.Error("Failed to get list routes", zap.Error(err))
 		http.Error(w, "Failed to get list routes", http.StatusInternalServerError)
 		return
 	}
+	if routes == nil {
+		routes = []domain.Route{}
+	}
+	// a page has the same array body as the full listing, the cursor of the
+	// following page goes into a header
+	if more {
+		w.Header().Set(nextCursorHeader, EncodeRouteCursor(routes[len(routes)-1]))
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(routes); err != nil {
 		logger.Error("Failed to encode response", zap.Error(err))
@@ -91,6 +255,26 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// streamRoutes writes all matching routes as a JSON array while they are
+// dumped, so the table is never held in memory
+func (h *Handler) streamRoutes(w http.ResponseWriter, r *http.Request, vrf uint32, filter domain.RouteFilter) {
+	w.Header().Set("Content-Type", "application/json")
+	out := newRouteArrayWriter(w)
+
+	err := h.ip.WalkRoutes(r.Context(), vrf, filter, out.Write)
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		logger.Error("Failed to stream routes", zap.Uint32("vrf", vrf), zap.Error(err))
+		if !out.started {
+			http.Error(w, "Failed to get list routes", http.StatusInternalServerError)
+		}
+		// the status is already sent, the truncated array signals the failure
+		return
+	}
+}
+
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	vrfStr := r.PathValue("vrf")
 	if vrfStr == "" {
@@ -146,6 +330,52 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) Lookup(w http.ResponseWriter, r *http.Request) {
+	vrfStr := r.PathValue("vrf")
+	vrfInt, err := strconv.ParseUint(vrfStr, 10, 32)
+	if err != nil {
+		logger.Error("Invalid VRF parameter", zap.String("vrf", vrfStr), zap.Error(err))
+		http.Error(w, "Invalid VRF ID", http.StatusBadRequest)
+		return
+	}
+	vrf := uint32(vrfInt)
+
+	addrStr := r.URL.Query().Get("addr")
+	addr := net.ParseIP(addrStr)
+	if addr == nil {
+		logger.Warn("Invalid addr parameter", zap.String("addr", addrStr))
+		http.Error(w, "Invalid addr parameter. Use an IPv4 or IPv6 address", http.StatusBadRequest)
+		return
+	}
+
+	recursive := false
+	if recursiveStr := r.URL.Query().Get("recursive"); recursiveStr != "" {
+		recursive, err = strconv.ParseBool(recursiveStr)
+		if err != nil {
+			logger.Warn("Invalid recursive parameter", zap.String("recursive", recursiveStr), zap.Error(err))
+			http.Error(w, "Invalid recursive parameter. Must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	lookup, err := h.ip.LookupRoute(r.Context(), addr, vrf, recursive)
+	if err != nil {
+		logger.Error("Failed to lookup route",
+			zap.String("addr", addrStr),
+			zap.Uint32("vrf", vrf),
+			zap.Error(err))
+		http.Error(w, "Route not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RouteLookupToResponse(lookup)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h *Handler) CreateVRF(w http.ResponseWriter, r *http.Request) {
 	var req CreateVRFRequest
 
@@ -197,3 +427,165 @@ func (h *Handler) DeleteVRF(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handler) GetFlowHash(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid ID VRF in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid ID VRF", http.StatusBadRequest)
+		return
+	}
+
+	ipv4, ipv6, err := h.ip.GetFlowHash(uint32(id))
+	if err != nil {
+		logger.Warn("Failed to get flow hash", zap.Uint64("vrf", id), zap.Error(err))
+		http.Error(w, "VRF not found", http.StatusNotFound)
+		return
+	}
+
+	resp := FlowHashResponse{
+		IPv4: FlowHashToResponse(ipv4),
+		IPv6: FlowHashToResponse(ipv6),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SetFlowHash(w http.ResponseWriter, r *http.Request) {
+	var req SetFlowHashRequest
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid ID VRF in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid ID VRF", http.StatusBadRequest)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	isIPv6, err := ParseAF(req.AF)
+	if err != nil {
+		logger.Warn("Invalid address family", zap.String("af", req.AF), zap.Error(err))
+		http.Error(w, "Invalid af. Use ip4 or ip6", http.StatusBadRequest)
+		return
+	}
+
+	err = h.ip.SetFlowHash(r.Context(), uint32(id), isIPv6, req.FlowHashConfig.ToDomain())
+	switch {
+	case errors.Is(err, ipServ.ErrVRFNotFound):
+		logger.Warn("VRF not found", zap.Uint64("vrf", id))
+		http.Error(w, "VRF not found", http.StatusNotFound)
+		return
+	case err != nil:
+		logger.Error("Failed to set flow hash", zap.Uint64("vrf", id), zap.Error(err))
+		http.Error(w, "Failed to set flow hash", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) CreateVRFLeak(w http.ResponseWriter, r *http.Request) {
+	var req CreateVRFLeakRequest
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid ID VRF in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid ID VRF", http.StatusBadRequest)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	leak, err := req.ToDomain(uint32(id))
+	if err != nil {
+		logger.Warn("Invalid VRF leak", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	leakID, err := h.ip.CreateVRFLeak(r.Context(), leak)
+	if err != nil {
+		logger.Error("Failed to create VRF leak", zap.Error(err))
+		http.Error(w, "Failed to create VRF leak", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(CreateVRFLeakResponse{ID: leakID}); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) ListVRFLeaks(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid ID VRF in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid ID VRF", http.StatusBadRequest)
+		return
+	}
+
+	leaks := h.ip.ListVRFLeaks(uint32(id))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(VRFLeaksToResponse(leaks)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DeleteVRFLeak(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid ID VRF in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid ID VRF", http.StatusBadRequest)
+		return
+	}
+	leakStr := r.PathValue("leak")
+	leakID, err := strconv.ParseUint(leakStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid ID leak in request", zap.String("leak", leakStr), zap.Error(err))
+		http.Error(w, "Invalid ID leak", http.StatusBadRequest)
+		return
+	}
+
+	err = h.ip.DeleteVRFLeak(r.Context(), uint32(id), uint32(leakID))
+	switch {
+	case errors.Is(err, ipServ.ErrLeakNotFound):
+		http.Error(w, "VRF leak not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to delete VRF leak", zap.Uint64("leak", leakID), zap.Error(err))
+		http.Error(w, "Failed to delete VRF leak", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ResolveNextHops replaces interface references of the next hops with their index
+func ResolveNextHops(ctx context.Context, resolver service.InterfaceResolver, nextHops []NextHopRequest) error {
+	for i := range nextHops {
+		if nextHops[i].Interface == "" {
+			continue
+		}
+		ifIndex, err := resolver.ResolveInterface(ctx, nextHops[i].Interface)
+		if err != nil {
+			return err
+		}
+		nextHops[i].IfIndex = ifIndex
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package ip
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
 
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 )
@@ -15,8 +19,47 @@ func (r *AddRouteRequest) ToDomain() (*domain.Route, error) {
 
 	prefix, _ := ipnet.Mask.Size()
 
-	nextHops := make([]domain.NextHop, 0, len(r.NextHops))
-	for _, nh := range r.NextHops {
+	nextHops, err := NextHopsToDomain(r.NextHops)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Route{
+		Dst: domain.IPWithPrefix{
+			Address: ip.String(),
+			Prefix:  uint8(prefix),
+		},
+		VRF:      r.VRF,
+		NextHops: nextHops,
+	}, nil
+}
+
+func (r *PatchRouteRequest) ToDomain() (domain.IPWithPrefix, []domain.NextHop, []domain.NextHop, error) {
+	ip, ipnet, err := net.ParseCIDR(r.Destination)
+	if err != nil {
+		return domain.IPWithPrefix{}, nil, nil, fmt.Errorf("parse destination: %w", err)
+	}
+	prefix, _ := ipnet.Mask.Size()
+
+	add, err := NextHopsToDomain(r.Add)
+	if err != nil {
+		return domain.IPWithPrefix{}, nil, nil, fmt.Errorf("add: %w", err)
+	}
+	remove, err := NextHopsToDomain(r.Remove)
+	if err != nil {
+		return domain.IPWithPrefix{}, nil, nil, fmt.Errorf("remove: %w", err)
+	}
+
+	dst := domain.IPWithPrefix{
+		Address: ip.String(),
+		Prefix:  uint8(prefix),
+	}
+	return dst, add, remove, nil
+}
+
+func NextHopsToDomain(reqs []NextHopRequest) ([]domain.NextHop, error) {
+	nextHops := make([]domain.NextHop, 0, len(reqs))
+	for _, nh := range reqs {
 		var nextHopIP net.IP
 		if nh.IP != "" {
 			nextHopIP = net.ParseIP(nh.IP)
@@ -25,22 +68,53 @@ func (r *AddRouteRequest) ToDomain() (*domain.Route, error) {
 			}
 		}
 
+		nhType, err := domain.ParseNextHopType(nh.Type)
+		if err != nil {
+			return nil, err
+		}
+		if nh.Drop {
+			nhType = domain.NextHopDrop
+		}
+		var nextVRF uint32
+		if nh.NextVRF != nil {
+			nextVRF = *nh.NextVRF
+			if nh.Type == "" && !nh.Drop {
+				nhType = domain.NextHopLookup
+			}
+		}
+
 		nextHops = append(nextHops, domain.NextHop{
-			IP:      nextHopIP,
-			IfIndex: nh.IfIndex,
-			Weight:  nh.Weight,
-			Drop:    nh.Drop,
+			Type:               nhType,
+			IP:                 nextHopIP,
+			IfIndex:            nh.IfIndex,
+			Weight:             nh.Weight,
+			Preference:         nh.Preference,
+			Drop:               nhType == domain.NextHopDrop,
+			NextVRF:            nextVRF,
+			UDPEncapID:         nh.UDPEncapID,
+			ResolveViaHost:     nh.ResolveViaHost,
+			ResolveViaAttached: nh.ResolveViaAttached,
+			Labels:             LabelsToDomain(nh.OutLabels),
 		})
 	}
 
-	return &domain.Route{
-		Dst: domain.IPWithPrefix{
-			Address: ip.String(),
-			Prefix:  uint8(prefix),
-		},
-		VRF:      r.VRF,
-		NextHops: nextHops,
-	}, nil
+	return nextHops, nil
+}
+
+func LabelsToDomain(reqs []LabelRequest) []domain.MPLSLabel {
+	if len(reqs) == 0 {
+		return nil
+	}
+	labels := make([]domain.MPLSLabel, 0, len(reqs))
+	for _, l := range reqs {
+		labels = append(labels, domain.MPLSLabel{
+			Label:   l.Label,
+			TTL:     l.TTL,
+			Exp:     l.Exp,
+			Uniform: l.Uniform,
+		})
+	}
+	return labels
 }
 
 func VRFToResponse(domains []domain.VRF) []VRFResponse {
@@ -48,13 +122,208 @@ func VRFToResponse(domains []domain.VRF) []VRFResponse {
 
 	for _, v := range domains {
 		res = append(res, VRFResponse{
-			ID:         v.ID,
-			Name:       v.Name,
-			IPv4:       v.IPv4,
-			IPv6:       v.IPv6,
-			RouteCount: v.RouteCount,
+			ID:           v.ID,
+			Name:         v.Name,
+			IPv4:         v.IPv4,
+			IPv6:         v.IPv6,
+			RouteCount:   v.RouteCount,
+			FlowHashIPv4: FlowHashToResponse(v.FlowHashIPv4),
+			FlowHashIPv6: FlowHashToResponse(v.FlowHashIPv6),
+		})
+	}
+
+	return res
+}
+
+func FlowHashToResponse(flowHash *domain.FlowHash) *FlowHashConfig {
+	if flowHash == nil {
+		return nil
+	}
+	return &FlowHashConfig{
+		SrcIP:     flowHash.SrcIP,
+		DstIP:     flowHash.DstIP,
+		SrcPort:   flowHash.SrcPort,
+		DstPort:   flowHash.DstPort,
+		Proto:     flowHash.Proto,
+		Reverse:   flowHash.Reverse,
+		Symmetric: flowHash.Symmetric,
+		FlowLabel: flowHash.FlowLabel,
+	}
+}
+
+func (c FlowHashConfig) ToDomain() domain.FlowHash {
+	return domain.FlowHash{
+		SrcIP:     c.SrcIP,
+		DstIP:     c.DstIP,
+		SrcPort:   c.SrcPort,
+		DstPort:   c.DstPort,
+		Proto:     c.Proto,
+		Reverse:   c.Reverse,
+		Symmetric: c.Symmetric,
+		FlowLabel: c.FlowLabel,
+	}
+}
+
+// ParseAF reports whether af names IPv6, accepted values are ip4, ipv4, ip6 and ipv6
+func ParseAF(af string) (bool, error) {
+	switch af {
+	case "ip4", "ipv4":
+		return false, nil
+	case "ip6", "ipv6":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid address family: %q", af)
+	}
+}
+
+func (r *CreateVRFLeakRequest) ToDomain(tenantVRF uint32) (domain.VRFLeak, error) {
+	imports, err := parsePrefixes(r.Import)
+	if err != nil {
+		return domain.VRFLeak{}, fmt.Errorf("import: %w", err)
+	}
+	exports, err := parsePrefixes(r.Export)
+	if err != nil {
+		return domain.VRFLeak{}, fmt.Errorf("export: %w", err)
+	}
+
+	return domain.VRFLeak{
+		TenantVRF: tenantVRF,
+		SharedVRF: r.SharedVRF,
+		Import:    imports,
+		Export:    exports,
+	}, nil
+}
+
+func parsePrefixes(prefixes []string) ([]domain.IPWithPrefix, error) {
+	res := make([]domain.IPWithPrefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		_, ipnet, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("parse prefix %q: %w", p, err)
+		}
+		ones, _ := ipnet.Mask.Size()
+		res = append(res, domain.IPWithPrefix{
+			Address: ipnet.IP.String(),
+			Prefix:  uint8(ones),
 		})
 	}
+	return res, nil
+}
+
+func prefixToString(prefix domain.IPWithPrefix) string {
+	return prefix.Address + "/" + strconv.Itoa(int(prefix.Prefix))
+}
+
+func prefixesToStrings(prefixes []domain.IPWithPrefix) []string {
+	res := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		res = append(res, prefixToString(p))
+	}
+	return res
+}
 
+func VRFLeaksToResponse(leaks []domain.VRFLeak) []VRFLeakResponse {
+	res := make([]VRFLeakResponse, 0, len(leaks))
+	for _, l := range leaks {
+		res = append(res, VRFLeakResponse{
+			ID:        l.ID,
+			TenantVRF: l.TenantVRF,
+			SharedVRF: l.SharedVRF,
+			Import:    prefixesToStrings(l.Import),
+			Export:    prefixesToStrings(l.Export),
+		})
+	}
 	return res
 }
+
+func RouteLookupToResponse(lookup domain.RouteLookup) RouteLookupResponse {
+	chain := make([]RouteResolutionResponse, 0, len(lookup.Chain))
+	for _, step := range lookup.Chain {
+		chain = append(chain, RouteResolutionResponse{
+			Address: step.Address.String(),
+			VRF:     step.VRF,
+			Prefix:  prefixToString(step.Route.Dst),
+			Paths:   step.Route.NextHops,
+		})
+	}
+
+	return RouteLookupResponse{
+		Address: lookup.Address.String(),
+		VRF:     lookup.VRF,
+		Prefix:  prefixToString(lookup.Route.Dst),
+		Paths:   lookup.Route.NextHops,
+		Chain:   chain,
+	}
+}
+
+// maxPageLimit bounds the page size of route listings
+const maxPageLimit = 10000
+
+// nextCursorHeader carries the cursor of the following page, it is absent on
+// the last page
+const nextCursorHeader = "X-Next-Cursor"
+
+// ParseRouteFilter reads af, within, type and include_system from the query,
+// the interface filter is resolved by the handler
+func ParseRouteFilter(query url.Values) (domain.RouteFilter, error) {
+	filter := domain.RouteFilter{IPv4: true, IPv6: true, IncludeSystem: true}
+
+	if af := query.Get("af"); af != "" {
+		isIPv6, err := ParseAF(af)
+		if err != nil {
+			return filter, fmt.Errorf("invalid af parameter %q. Use ip4 or ip6", af)
+		}
+		filter.IPv4 = !isIPv6
+		filter.IPv6 = isIPv6
+	}
+
+	if within := query.Get("within"); within != "" {
+		prefix, err := netip.ParsePrefix(within)
+		if err != nil {
+			return filter, fmt.Errorf("invalid within parameter %q. Use: address/prefix", within)
+		}
+		prefix = prefix.Masked()
+		filter.Within = &prefix
+	}
+
+	if typeStr := query.Get("type"); typeStr != "" {
+		nhType, err := domain.ParseNextHopType(typeStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid type parameter: %w", err)
+		}
+		filter.PathType = &nhType
+	}
+
+	if systemStr := query.Get("include_system"); systemStr != "" {
+		includeSystem, err := strconv.ParseBool(systemStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid include_system parameter %q. Must be a boolean", systemStr)
+		}
+		filter.IncludeSystem = includeSystem
+	}
+
+	return filter, nil
+}
+
+// EncodeRouteCursor returns the opaque cursor pointing after the route
+func EncodeRouteCursor(route domain.Route) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%s/%d", route.Dst.Address, route.Dst.Prefix)))
+}
+
+// DecodeRouteCursor returns the prefix encoded in the cursor, an empty
+// cursor yields the zero prefix which starts from the beginning
+func DecodeRouteCursor(cursor string) (netip.Prefix, error) {
+	if cursor == "" {
+		return netip.Prefix{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("decode cursor: %w", err)
+	}
+	prefix, err := netip.ParsePrefix(string(raw))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("parse cursor: %w", err)
+	}
+	return prefix.Masked(), nil
+}
@@ -15,14 +15,67 @@ type AddRouteRequest struct {
 	NextHops    []NextHopRequest `json:"next_hops"`
 }
 
+// PatchRouteRequest adds and removes individual paths of a route, paths are
+// matched by type, ip, interface, next_vrf and udp_encap_id
+type PatchRouteRequest struct {
+	Destination string           `json:"destination"`
+	VRF         uint32           `json:"vrf"`
+	Add         []NextHopRequest `json:"add"`
+	Remove      []NextHopRequest `json:"remove"`
+}
+
 type NextHopRequest struct {
-	IP      string `json:"ip,omitempty"`
-	IfIndex uint32 `json:"if_index"`
-	Weight  uint8  `json:"weight"`
-	Drop    bool   `json:"drop"`
+	// Type is one of normal, drop, local, unreachable, prohibit, lookup,
+	// source-lookup, interface-rx, dvr, udp-encap; normal by default,
+	// lookup when only next_vrf is given
+	Type string `json:"type,omitempty"`
+	IP   string `json:"ip,omitempty"`
+	// Interface is an interface name or index, it takes precedence over IfIndex
+	Interface          string  `json:"interface,omitempty"`
+	IfIndex            uint32  `json:"if_index"`
+	Weight             uint8   `json:"weight"`
+	Preference         uint8   `json:"preference,omitempty"`
+	Drop               bool    `json:"drop"`
+	NextVRF            *uint32 `json:"next_vrf,omitempty"`
+	UDPEncapID         uint32  `json:"udp_encap_id,omitempty"`
+	ResolveViaHost     bool    `json:"resolve_via_host,omitempty"`
+	ResolveViaAttached bool    `json:"resolve_via_attached,omitempty"`
+	// OutLabels is the MPLS label stack pushed on the path, outermost first
+	OutLabels []LabelRequest `json:"out_labels,omitempty"`
+}
+
+type LabelRequest struct {
+	Label   uint32 `json:"label"`
+	TTL     uint8  `json:"ttl,omitempty"`
+	Exp     uint8  `json:"exp,omitempty"`
+	Uniform bool   `json:"uniform,omitempty"`
 }
 
 type CreateVRFRequest struct {
 	ID   uint32 `json:"id"`
 	Name string `json:"name"`
 }
+
+// FlowHashConfig lists the packet fields hashed to choose among ECMP paths
+type FlowHashConfig struct {
+	SrcIP     bool `json:"src_ip"`
+	DstIP     bool `json:"dst_ip"`
+	SrcPort   bool `json:"src_port"`
+	DstPort   bool `json:"dst_port"`
+	Proto     bool `json:"proto"`
+	Reverse   bool `json:"reverse"`
+	Symmetric bool `json:"symmetric"`
+	FlowLabel bool `json:"flow_label"`
+}
+
+type SetFlowHashRequest struct {
+	// AF is ip4 or ip6
+	AF string `json:"af"`
+	FlowHashConfig
+}
+
+type CreateVRFLeakRequest struct {
+	SharedVRF uint32   `json:"shared_vrf"`
+	Import    []string `json:"import"`
+	Export    []string `json:"export"`
+}
@@ -1,9 +1,46 @@
 package ip
 
+import "github.com/NikolayStepanov/RapidVPP/internal/domain"
+
 type VRFResponse struct {
-	ID         uint32 `json:"id"`
-	Name       string `json:"name"`
-	IPv4       bool   `json:"ipv4"`
-	IPv6       bool   `json:"ipv6"`
-	RouteCount int    `json:"route_count"`
+	ID           uint32          `json:"id"`
+	Name         string          `json:"name"`
+	IPv4         bool            `json:"ipv4"`
+	IPv6         bool            `json:"ipv6"`
+	RouteCount   int             `json:"route_count"`
+	FlowHashIPv4 *FlowHashConfig `json:"flow_hash_ipv4,omitempty"`
+	FlowHashIPv6 *FlowHashConfig `json:"flow_hash_ipv6,omitempty"`
+}
+
+// FlowHashResponse omits an address family whose hash is unknown
+type FlowHashResponse struct {
+	IPv4 *FlowHashConfig `json:"ipv4,omitempty"`
+	IPv6 *FlowHashConfig `json:"ipv6,omitempty"`
+}
+
+type VRFLeakResponse struct {
+	ID        uint32   `json:"id"`
+	TenantVRF uint32   `json:"tenant_vrf"`
+	SharedVRF uint32   `json:"shared_vrf"`
+	Import    []string `json:"import"`
+	Export    []string `json:"export"`
+}
+
+type CreateVRFLeakResponse struct {
+	ID uint32 `json:"id"`
+}
+
+type RouteLookupResponse struct {
+	Address string                    `json:"address"`
+	VRF     uint32                    `json:"vrf"`
+	Prefix  string                    `json:"prefix"`
+	Paths   []domain.NextHop          `json:"paths"`
+	Chain   []RouteResolutionResponse `json:"chain,omitempty"`
+}
+
+type RouteResolutionResponse struct {
+	Address string           `json:"address"`
+	VRF     uint32           `json:"vrf"`
+	Prefix  string           `json:"prefix"`
+	Paths   []domain.NextHop `json:"paths"`
 }
@@ -0,0 +1,56 @@
+package ip
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// flushEvery is the number of routes written between flushes of the response
+const flushEvery = 1000
+
+// routeArrayWriter encodes routes as the elements of a JSON array one by one
+type routeArrayWriter struct {
+	w       io.Writer
+	enc     *json.Encoder
+	flusher http.Flusher
+	started bool
+	count   int
+}
+
+func newRouteArrayWriter(w http.ResponseWriter) *routeArrayWriter {
+	flusher, _ := w.(http.Flusher)
+	return &routeArrayWriter{w: w, enc: json.NewEncoder(w), flusher: flusher}
+}
+
+func (a *routeArrayWriter) Write(route domain.Route) error {
+	sep := ","
+	if !a.started {
+		sep = "["
+		a.started = true
+	}
+	if _, err := io.WriteString(a.w, sep); err != nil {
+		return err
+	}
+	if err := a.enc.Encode(route); err != nil {
+		return err
+	}
+
+	a.count++
+	if a.flusher != nil && a.count%flushEvery == 0 {
+		a.flusher.Flush()
+	}
+	return nil
+}
+
+func (a *routeArrayWriter) Close() error {
+	end := "]\n"
+	if !a.started {
+		end = "[]\n"
+		a.started = true
+	}
+	_, err := io.WriteString(a.w, end)
+	return err
+}
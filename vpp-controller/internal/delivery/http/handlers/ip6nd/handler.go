@@ -0,0 +1,169 @@
+package ip6nd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	ip6nd    service.IP6ND
+	resolver service.InterfaceResolver
+}
+
+func NewHandler(ip6nd service.IP6ND, resolver service.InterfaceResolver) *Handler {
+	return &Handler{ip6nd: ip6nd, resolver: resolver}
+}
+
+func (h *Handler) EnableIPv6(w http.ResponseWriter, r *http.Request) {
+	var req EnableIPv6Request
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ip6nd.EnableIPv6(r.Context(), ifIndex, req.Enable); err != nil {
+		logger.Error("Failed to enable IPv6 on interface", zap.Error(err))
+		http.Error(w, "Failed to enable IPv6 on interface", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListRA(w http.ResponseWriter, r *http.Request) {
+	infos, err := h.ip6nd.ListRA(r.Context(), 0xFFFFFFFF)
+	if err != nil {
+		logger.Error("Failed to list RA config", zap.Error(err))
+		http.Error(w, "Failed to list RA config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RAInfosToResponse(infos)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetRA(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	infos, err := h.ip6nd.ListRA(r.Context(), ifIndex)
+	if err != nil {
+		logger.Error("Failed to get RA config", zap.Error(err))
+		http.Error(w, "Failed to get RA config", http.StatusInternalServerError)
+		return
+	}
+	if len(infos) == 0 {
+		http.Error(w, "IPv6 is not enabled on interface", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RAInfoToResponse(infos[0])); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SetRA(w http.ResponseWriter, r *http.Request) {
+	var req RAConfigRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	config, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid RA config", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ip6nd.SetRAConfig(r.Context(), ifIndex, config); err != nil {
+		logger.Error("Failed to set RA config", zap.Error(err))
+		http.Error(w, "Failed to set RA config", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) AddRAPrefix(w http.ResponseWriter, r *http.Request) {
+	var req RAPrefixRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	prefix, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid RA prefix", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ip6nd.AddRAPrefix(r.Context(), ifIndex, prefix); err != nil {
+		logger.Error("Failed to add RA prefix", zap.Error(err))
+		http.Error(w, "Failed to add RA prefix", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) DeleteRAPrefix(w http.ResponseWriter, r *http.Request) {
+	var req DeleteRAPrefixRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	prefix, err := ParseIPv6Prefix(req.Prefix)
+	if err != nil {
+		logger.Warn("Invalid RA prefix", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ip6nd.DeleteRAPrefix(r.Context(), ifIndex, prefix); err != nil {
+		logger.Error("Failed to delete RA prefix", zap.Error(err))
+		http.Error(w, "Failed to delete RA prefix", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,109 @@
+package ip6nd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+func (r *RAConfigRequest) ToDomain() (domain.RAConfig, error) {
+	if r.MinInterval != 0 && r.MaxInterval != 0 && r.MinInterval > r.MaxInterval {
+		return domain.RAConfig{}, fmt.Errorf("min_interval %d is greater than max_interval %d", r.MinInterval, r.MaxInterval)
+	}
+
+	return domain.RAConfig{
+		Suppress:         r.Suppress,
+		Managed:          r.Managed,
+		Other:            r.Other,
+		LinkLayerAddress: r.LinkLayerAddress,
+		SendUnicast:      r.SendUnicast,
+		Cease:            r.Cease,
+		RouterLifetime:   r.RouterLifetime,
+		MaxInterval:      r.MaxInterval,
+		MinInterval:      r.MinInterval,
+		InitialCount:     r.InitialCount,
+		InitialInterval:  r.InitialInterval,
+	}, nil
+}
+
+func (r *RAPrefixRequest) ToDomain() (domain.RAPrefix, error) {
+	prefix, err := ParseIPv6Prefix(r.Prefix)
+	if err != nil {
+		return domain.RAPrefix{}, err
+	}
+	if r.PreferredLifetime > r.ValidLifetime {
+		return domain.RAPrefix{}, fmt.Errorf("preferred_lifetime %d is greater than valid_lifetime %d", r.PreferredLifetime, r.ValidLifetime)
+	}
+
+	return domain.RAPrefix{
+		Prefix:            prefix,
+		UseDefault:        r.UseDefault,
+		NoAdvertise:       r.NoAdvertise,
+		OffLink:           r.OffLink,
+		NoAutoconfig:      r.NoAutoconfig,
+		NoOnlink:          r.NoOnlink,
+		ValidLifetime:     r.ValidLifetime,
+		PreferredLifetime: r.PreferredLifetime,
+	}, nil
+}
+
+func ParseIPv6Prefix(s string) (domain.IPWithPrefix, error) {
+	ip, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return domain.IPWithPrefix{}, fmt.Errorf("parse prefix: %w", err)
+	}
+	if ip.To4() != nil {
+		return domain.IPWithPrefix{}, fmt.Errorf("prefix %s is not IPv6", s)
+	}
+	ones, _ := ipnet.Mask.Size()
+
+	return domain.IPWithPrefix{
+		Address: ipnet.IP.String(),
+		Prefix:  uint8(ones),
+	}, nil
+}
+
+func RAInfosToResponse(infos []domain.RAInfo) []RAResponse {
+	res := make([]RAResponse, 0, len(infos))
+	for _, info := range infos {
+		res = append(res, RAInfoToResponse(info))
+	}
+	return res
+}
+
+func RAInfoToResponse(info domain.RAInfo) RAResponse {
+	prefixes := make([]RAPrefixResponse, 0, len(info.Prefixes))
+	for _, p := range info.Prefixes {
+		prefixes = append(prefixes, RAPrefixResponse{
+			Prefix:            p.Prefix.Address + "/" + strconv.Itoa(int(p.Prefix.Prefix)),
+			NoAdvertise:       p.NoAdvertise,
+			NoAutoconfig:      p.NoAutoconfig,
+			NoOnlink:          p.NoOnlink,
+			ValidLifetime:     p.ValidLifetime,
+			PreferredLifetime: p.PreferredLifetime,
+		})
+	}
+
+	return RAResponse{
+		InterfaceID:           info.InterfaceID,
+		SendRA:                info.SendRA,
+		Managed:               info.Managed,
+		Other:                 info.Other,
+		LinkLayerAddress:      info.LinkLayerAddress,
+		SendUnicast:           info.SendUnicast,
+		Cease:                 info.Cease,
+		CurHopLimit:           info.CurHopLimit,
+		RouterLifetime:        info.RouterLifetime,
+		ReachableTime:         info.ReachableTime,
+		RetransmitInterval:    info.RetransmitInterval,
+		LinkMTU:               info.LinkMTU,
+		MaxInterval:           info.MaxInterval,
+		MinInterval:           info.MinInterval,
+		AdvertisementsSent:    info.AdvertisementsSent,
+		SolicitationsReceived: info.SolicitationsReceived,
+		SolicitationsDropped:  info.SolicitationsDropped,
+		Prefixes:              prefixes,
+	}
+}
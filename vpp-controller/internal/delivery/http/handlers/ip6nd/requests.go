@@ -0,0 +1,34 @@
+package ip6nd
+
+type EnableIPv6Request struct {
+	Enable bool `json:"enable"`
+}
+
+type RAConfigRequest struct {
+	Suppress         bool    `json:"suppress"`
+	Managed          bool    `json:"managed"`
+	Other            bool    `json:"other"`
+	LinkLayerAddress bool    `json:"link_layer_address"`
+	SendUnicast      bool    `json:"send_unicast"`
+	Cease            bool    `json:"cease"`
+	RouterLifetime   *uint32 `json:"router_lifetime,omitempty"`
+	MaxInterval      uint32  `json:"max_interval,omitempty"`
+	MinInterval      uint32  `json:"min_interval,omitempty"`
+	InitialCount     uint32  `json:"initial_count,omitempty"`
+	InitialInterval  uint32  `json:"initial_interval,omitempty"`
+}
+
+type RAPrefixRequest struct {
+	Prefix            string `json:"prefix"`
+	UseDefault        bool   `json:"use_default"`
+	NoAdvertise       bool   `json:"no_advertise"`
+	OffLink           bool   `json:"off_link"`
+	NoAutoconfig      bool   `json:"no_autoconfig"`
+	NoOnlink          bool   `json:"no_onlink"`
+	ValidLifetime     uint32 `json:"valid_lifetime"`
+	PreferredLifetime uint32 `json:"preferred_lifetime"`
+}
+
+type DeleteRAPrefixRequest struct {
+	Prefix string `json:"prefix"`
+}
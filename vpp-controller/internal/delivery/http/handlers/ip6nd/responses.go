@@ -0,0 +1,31 @@
+package ip6nd
+
+type RAResponse struct {
+	InterfaceID           uint32             `json:"interface_id"`
+	SendRA                bool               `json:"send_ra"`
+	Managed               bool               `json:"managed"`
+	Other                 bool               `json:"other"`
+	LinkLayerAddress      bool               `json:"link_layer_address"`
+	SendUnicast           bool               `json:"send_unicast"`
+	Cease                 bool               `json:"cease"`
+	CurHopLimit           uint8              `json:"cur_hop_limit"`
+	RouterLifetime        uint16             `json:"router_lifetime"`
+	ReachableTime         uint32             `json:"reachable_time"`
+	RetransmitInterval    uint32             `json:"retransmit_interval"`
+	LinkMTU               uint32             `json:"link_mtu"`
+	MaxInterval           float64            `json:"max_interval"`
+	MinInterval           float64            `json:"min_interval"`
+	AdvertisementsSent    uint32             `json:"advertisements_sent"`
+	SolicitationsReceived uint32             `json:"solicitations_received"`
+	SolicitationsDropped  uint32             `json:"solicitations_dropped"`
+	Prefixes              []RAPrefixResponse `json:"prefixes"`
+}
+
+type RAPrefixResponse struct {
+	Prefix            string `json:"prefix"`
+	NoAdvertise       bool   `json:"no_advertise"`
+	NoAutoconfig      bool   `json:"no_autoconfig"`
+	NoOnlink          bool   `json:"no_onlink"`
+	ValidLifetime     uint32 `json:"valid_lifetime"`
+	PreferredLifetime uint32 `json:"preferred_lifetime"`
+}
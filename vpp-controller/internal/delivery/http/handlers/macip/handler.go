@@ -0,0 +1,180 @@
+package macip
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	macipServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/macip"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	macip    service.MACIP
+	resolver service.InterfaceResolver
+}
+
+func NewHandler(macip service.MACIP, resolver service.InterfaceResolver) *Handler {
+	return &Handler{macip: macip, resolver: resolver}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	acls, err := h.macip.List(r.Context())
+	if err != nil {
+		logger.Error("Failed to list MACIP ACLs", zap.Error(err))
+		http.Error(w, "Failed to list MACIP ACLs", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, ListACLResponse{ACLsToResponse(acls)})
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	rules, err := ConvertRulesRequestToDomain(req.Rules)
+	if err != nil {
+		logger.Warn("Invalid MACIP ACL rules", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.macip.Create(r.Context(), req.Name, rules)
+	if err != nil {
+		logger.Error("Failed to create MACIP ACL", zap.Error(err))
+		http.Error(w, "Failed to create MACIP ACL", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
+
+func (h *Handler) Replace(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseACLID(w, r)
+	if !ok {
+		return
+	}
+	var req ReplaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	rules, err := ConvertRulesRequestToDomain(req.Rules)
+	if err != nil {
+		logger.Warn("Invalid MACIP ACL rules", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.macip.Replace(r.Context(), id, rules)
+	switch {
+	case errors.Is(err, macipServ.ErrNotFound):
+		logger.Warn("MACIP ACL not found", zap.Uint32("id", uint32(id)))
+		http.Error(w, "MACIP ACL not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to replace MACIP ACL", zap.Uint32("id", uint32(id)), zap.Error(err))
+		http.Error(w, "Failed to replace MACIP ACL", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseACLID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.macip.Delete(r.Context(), id)
+	switch {
+	case errors.Is(err, macipServ.ErrNotFound):
+		logger.Warn("MACIP ACL not found", zap.Uint32("id", uint32(id)))
+		http.Error(w, "MACIP ACL not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to delete MACIP ACL", zap.Uint32("id", uint32(id)), zap.Error(err))
+		http.Error(w, "Failed to delete MACIP ACL", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	assignments, err := h.macip.ListAssignments(r.Context())
+	if err != nil {
+		logger.Error("Failed to list MACIP ACL interfaces", zap.Error(err))
+		http.Error(w, "Failed to list MACIP ACL interfaces", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, AssignmentsToResponse(assignments))
+}
+
+func (h *Handler) Assign(w http.ResponseWriter, r *http.Request) {
+	var req AssignRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.macip.Assign(r.Context(), ifIndex, domain.AclID(req.ACLID))
+	switch {
+	case errors.Is(err, macipServ.ErrNotFound):
+		logger.Warn("MACIP ACL not found", zap.Uint32("id", req.ACLID))
+		http.Error(w, "MACIP ACL not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to assign MACIP ACL", zap.Uint32("interface", ifIndex), zap.Error(err))
+		http.Error(w, "Failed to assign MACIP ACL", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) Unassign(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+
+	err = h.macip.Unassign(r.Context(), ifIndex)
+	switch {
+	case errors.Is(err, macipServ.ErrAssignmentNotFound):
+		logger.Warn("No MACIP ACL assigned", zap.Uint32("interface", ifIndex))
+		http.Error(w, "No MACIP ACL assigned to interface", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to unassign MACIP ACL", zap.Uint32("interface", ifIndex), zap.Error(err))
+		http.Error(w, "Failed to unassign MACIP ACL", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseACLID(w http.ResponseWriter, r *http.Request) (domain.AclID, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid MACIP ACL ID in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid MACIP ACL ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return domain.AclID(id), true
+}
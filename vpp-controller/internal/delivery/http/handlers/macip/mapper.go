@@ -0,0 +1,94 @@
+package macip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+var fullMACMask = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+func ConvertRulesRequestToDomain(rules []RulesRequest) ([]domain.MACIPRule, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("rules list is empty")
+	}
+
+	out := make([]domain.MACIPRule, 0, len(rules))
+	for i, r := range rules {
+		action := domain.ACLAction(r.Action)
+		if action != domain.ACLDeny && action != domain.ACLPermit {
+			return nil, fmt.Errorf("rule %d: invalid action %d, expected 0 (deny) or 1 (permit)", i, r.Action)
+		}
+		mac, err := parseMAC(r.MAC)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: mac: %w", i, err)
+		}
+		mask := fullMACMask
+		if r.MACMask != "" {
+			mask, err = parseMAC(r.MACMask)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: mac_mask: %w", i, err)
+			}
+		}
+		if r.Src.Address == "" {
+			return nil, fmt.Errorf("rule %d: source address is empty", i)
+		}
+		if net.ParseIP(r.Src.Address) == nil {
+			return nil, fmt.Errorf("rule %d: invalid source address %q", i, r.Src.Address)
+		}
+
+		out = append(out, domain.MACIPRule{
+			Action:  action,
+			MAC:     mac,
+			MACMask: mask,
+			Src:     domain.IPWithPrefix{Address: r.Src.Address, Prefix: r.Src.Prefix},
+		})
+	}
+	return out, nil
+}
+
+func parseMAC(s string) (net.HardwareAddr, error) {
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("%q is not a 48-bit mac address", s)
+	}
+	return mac, nil
+}
+
+func ACLToResponse(info domain.MACIPACL) ACLResponse {
+	rules := make([]RulesResponse, len(info.Rules))
+	for i, r := range info.Rules {
+		rules[i] = RulesResponse{
+			Action:     uint8(r.Action),
+			ActionName: r.Action.String(),
+			MAC:        r.MAC.String(),
+			MACMask:    r.MACMask.String(),
+			Src:        IPWithPrefix{Address: r.Src.Address, Prefix: r.Src.Prefix},
+		}
+	}
+	return ACLResponse{
+		ID:    uint32(info.ID),
+		Name:  info.Name,
+		Rules: rules,
+	}
+}
+
+func ACLsToResponse(acls []domain.MACIPACL) []ACLResponse {
+	responses := make([]ACLResponse, len(acls))
+	for i, acl := range acls {
+		responses[i] = ACLToResponse(acl)
+	}
+	return responses
+}
+
+func AssignmentsToResponse(assignments []domain.MACIPAssignment) []AssignmentResponse {
+	res := make([]AssignmentResponse, 0, len(assignments))
+	for _, a := range assignments {
+		res = append(res, AssignmentResponse{InterfaceID: a.InterfaceID, ACLID: uint32(a.ACLID)})
+	}
+	return res
+}
@@ -0,0 +1,28 @@
+package macip
+
+type CreateRequest struct {
+	Name  string         `json:"name"`
+	Rules []RulesRequest `json:"rules"`
+}
+
+type ReplaceRequest struct {
+	Rules []RulesRequest `json:"rules"`
+}
+
+type RulesRequest struct {
+	// Action is 0 (deny) or 1 (permit)
+	Action uint8  `json:"action"`
+	MAC    string `json:"mac"`
+	// MACMask selects the compared MAC bits, ff:ff:ff:ff:ff:ff by default
+	MACMask string       `json:"mac_mask,omitempty"`
+	Src     IPWithPrefix `json:"src"`
+}
+
+type IPWithPrefix struct {
+	Address string `json:"address"`
+	Prefix  uint8  `json:"prefix"`
+}
+
+type AssignRequest struct {
+	ACLID uint32 `json:"acl_id"`
+}
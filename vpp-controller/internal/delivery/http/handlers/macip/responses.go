@@ -0,0 +1,24 @@
+package macip
+
+type ACLResponse struct {
+	ID    uint32          `json:"id"`
+	Name  string          `json:"name"`
+	Rules []RulesResponse `json:"rules"`
+}
+
+type ListACLResponse struct {
+	ACLs []ACLResponse `json:"acls"`
+}
+
+type RulesResponse struct {
+	Action     uint8        `json:"action"`
+	ActionName string       `json:"action_name"`
+	MAC        string       `json:"mac"`
+	MACMask    string       `json:"mac_mask"`
+	Src        IPWithPrefix `json:"src"`
+}
+
+type AssignmentResponse struct {
+	InterfaceID uint32 `json:"interface_id"`
+	ACLID       uint32 `json:"acl_id"`
+}
@@ -0,0 +1,213 @@
+package mpls
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	mplsServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/mpls"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	mpls     service.MPLS
+	resolver service.InterfaceResolver
+}
+
+func NewHandler(mpls service.MPLS, resolver service.InterfaceResolver) *Handler {
+	return &Handler{mpls: mpls, resolver: resolver}
+}
+
+func (h *Handler) EnableInterface(w http.ResponseWriter, r *http.Request) {
+	var req EnableMPLSRequest
+	idStr := r.PathValue("id")
+	ifIndex, err := h.resolver.ResolveInterface(r.Context(), idStr)
+	if err != nil {
+		response.ResolveError(w, "Invalid interface", err, zap.String("id", idStr))
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.mpls.EnableMPLS(r.Context(), ifIndex, req.Enable)
+	switch {
+	case errors.Is(err, mplsServ.ErrNotFound):
+		logger.Warn("MPLS interface or table 0 not found", zap.Error(err))
+		http.Error(w, "interface or MPLS table 0 not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to set MPLS on interface", zap.Error(err))
+		http.Error(w, "Failed to set MPLS on interface", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) ListInterfaces(w http.ResponseWriter, r *http.Request) {
+	ifIndexes, err := h.mpls.ListMPLSInterfaces(r.Context())
+	if err != nil {
+		logger.Error("Failed to list MPLS interfaces", zap.Error(err))
+		http.Error(w, "Failed to list MPLS interfaces", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, InterfacesToResponse(ifIndexes))
+}
+
+func (h *Handler) ListTables(w http.ResponseWriter, r *http.Request) {
+	tables, err := h.mpls.ListMPLSTables(r.Context())
+	if err != nil {
+		logger.Error("Failed to list MPLS tables", zap.Error(err))
+		http.Error(w, "Failed to list MPLS tables", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, TablesToResponse(tables))
+}
+
+func (h *Handler) CreateTable(w http.ResponseWriter, r *http.Request) {
+	var req CreateTableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	table := domain.MPLSTable{ID: req.ID, Name: req.Name}
+	if err := h.mpls.CreateMPLSTable(r.Context(), table); err != nil {
+		logger.Error("Failed to create MPLS table", zap.Uint32("id", req.ID), zap.Error(err))
+		http.Error(w, "Failed to create MPLS table", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) DeleteTable(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTableID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.mpls.DeleteMPLSTable(r.Context(), id)
+	switch {
+	case errors.Is(err, mplsServ.ErrNotFound):
+		logger.Warn("MPLS table not found", zap.Uint32("id", id))
+		http.Error(w, "MPLS table not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to delete MPLS table", zap.Uint32("id", id), zap.Error(err))
+		http.Error(w, "Failed to delete MPLS table", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) ListRoutes(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTableID(w, r)
+	if !ok {
+		return
+	}
+
+	routes, err := h.mpls.ListMPLSRoutes(r.Context(), id)
+	if err != nil {
+		logger.Error("Failed to list MPLS routes", zap.Uint32("table", id), zap.Error(err))
+		http.Error(w, "Failed to list MPLS routes", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, RoutesToResponse(routes))
+}
+
+func (h *Handler) AddRoute(w http.ResponseWriter, r *http.Request) {
+	var req RouteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ifIndexes := make([]uint32, len(req.Paths))
+	for i, p := range req.Paths {
+		ifIndexes[i] = p.IfIndex
+		if p.Interface == "" {
+			continue
+		}
+		ifIndex, err := h.resolver.ResolveInterface(r.Context(), p.Interface)
+		if err != nil {
+			response.ResolveError(w, "Invalid next-hop interface", err, zap.String("interface", p.Interface))
+			return
+		}
+		ifIndexes[i] = ifIndex
+	}
+
+	route, err := req.ToDomain(ifIndexes)
+	if err != nil {
+		logger.Warn("Invalid MPLS route", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.mpls.AddMPLSRoute(r.Context(), route)
+	switch {
+	case errors.Is(err, mplsServ.ErrInvalid):
+		logger.Warn("Invalid MPLS route", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, mplsServ.ErrNotFound):
+		logger.Warn("MPLS table not found", zap.Uint32("table", route.Table))
+		http.Error(w, "MPLS table not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to add MPLS route", zap.Error(err))
+		http.Error(w, "Failed to add MPLS route", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (h *Handler) DeleteRoute(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTableID(w, r)
+	if !ok {
+		return
+	}
+	labelStr := r.PathValue("label")
+	label, err := strconv.ParseUint(labelStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid label in request", zap.String("label", labelStr), zap.Error(err))
+		http.Error(w, "Invalid label", http.StatusBadRequest)
+		return
+	}
+	eos := false
+	if eosStr := r.URL.Query().Get("eos"); eosStr != "" {
+		eos, err = strconv.ParseBool(eosStr)
+		if err != nil {
+			logger.Warn("Invalid eos parameter", zap.String("eos", eosStr), zap.Error(err))
+			http.Error(w, "Invalid eos parameter. Must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.mpls.DeleteMPLSRoute(r.Context(), id, uint32(label), eos)
+	switch {
+	case errors.Is(err, mplsServ.ErrNotFound):
+		logger.Warn("MPLS route not found", zap.Uint32("table", id), zap.Uint64("label", label))
+		http.Error(w, "MPLS route not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to delete MPLS route", zap.Error(err))
+		http.Error(w, "Failed to delete MPLS route", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseTableID(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid MPLS table ID in request", zap.String("id", idStr), zap.Error(err))
+		http.Error(w, "Invalid MPLS table ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint32(id), true
+}
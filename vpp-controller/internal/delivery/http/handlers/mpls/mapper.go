@@ -0,0 +1,93 @@
+package mpls
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/ip"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// ToDomain converts the request, ifIndexes holds the resolved interface of every path
+func (r *RouteRequest) ToDomain(ifIndexes []uint32) (domain.MPLSRoute, error) {
+	action, err := domain.ParseMPLSAction(r.Action)
+	if err != nil {
+		return domain.MPLSRoute{}, err
+	}
+
+	payloadIPv6 := false
+	if r.PayloadAF != "" {
+		payloadIPv6, err = ip.ParseAF(r.PayloadAF)
+		if err != nil {
+			return domain.MPLSRoute{}, err
+		}
+	}
+
+	nextHops := make([]domain.NextHop, 0, len(r.Paths))
+	for i, p := range r.Paths {
+		var nextHopIP net.IP
+		if p.IP != "" {
+			nextHopIP = net.ParseIP(p.IP)
+			if nextHopIP == nil {
+				return domain.MPLSRoute{}, fmt.Errorf("invalid next-hop IP: %s", p.IP)
+			}
+		}
+		nextHops = append(nextHops, domain.NextHop{
+			Type:       domain.NextHopNormal,
+			IP:         nextHopIP,
+			IfIndex:    ifIndexes[i],
+			Weight:     p.Weight,
+			Preference: p.Preference,
+			Labels:     ip.LabelsToDomain(p.OutLabels),
+		})
+	}
+
+	return domain.MPLSRoute{
+		Table:       r.Table,
+		Label:       r.Label,
+		EOS:         r.EOS,
+		PayloadIPv6: payloadIPv6,
+		Action:      action,
+		VRF:         r.VRF,
+		NextHops:    nextHops,
+	}, nil
+}
+
+func RoutesToResponse(routes []domain.MPLSRoute) []RouteResponse {
+	res := make([]RouteResponse, 0, len(routes))
+	for _, route := range routes {
+		resp := RouteResponse{
+			Table:     route.Table,
+			Label:     route.Label,
+			EOS:       route.EOS,
+			PayloadAF: "ip4",
+			Action:    route.Action.String(),
+			Paths:     route.NextHops,
+		}
+		if route.PayloadIPv6 {
+			resp.PayloadAF = "ip6"
+		}
+		if route.Action == domain.MPLSDisposition {
+			vrf := route.VRF
+			resp.VRF = &vrf
+		}
+		res = append(res, resp)
+	}
+	return res
+}
+
+func TablesToResponse(tables []domain.MPLSTable) []TableResponse {
+	res := make([]TableResponse, 0, len(tables))
+	for _, t := range tables {
+		res = append(res, TableResponse{ID: t.ID, Name: t.Name})
+	}
+	return res
+}
+
+func InterfacesToResponse(ifIndexes []uint32) []InterfaceResponse {
+	res := make([]InterfaceResponse, 0, len(ifIndexes))
+	for _, ifIndex := range ifIndexes {
+		res = append(res, InterfaceResponse{InterfaceID: ifIndex})
+	}
+	return res
+}
@@ -0,0 +1,35 @@
+package mpls
+
+import "github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/ip"
+
+type EnableMPLSRequest struct {
+	Enable bool `json:"enable"`
+}
+
+type CreateTableRequest struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+type RouteRequest struct {
+	Table uint32 `json:"table"`
+	Label uint32 `json:"label"`
+	EOS   bool   `json:"eos"`
+	// PayloadAF is ip4 (default) or ip6, the protocol below the bottom label
+	PayloadAF string `json:"payload_af,omitempty"`
+	// Action is swap, pop or disposition
+	Action string `json:"action"`
+	// VRF is the table disposition routes look the payload up in
+	VRF   uint32        `json:"vrf,omitempty"`
+	Paths []PathRequest `json:"paths,omitempty"`
+}
+
+type PathRequest struct {
+	IP string `json:"ip,omitempty"`
+	// Interface is an interface name or index, it takes precedence over IfIndex
+	Interface  string            `json:"interface,omitempty"`
+	IfIndex    uint32            `json:"if_index"`
+	Weight     uint8             `json:"weight,omitempty"`
+	Preference uint8             `json:"preference,omitempty"`
+	OutLabels  []ip.LabelRequest `json:"out_labels,omitempty"`
+}
@@ -0,0 +1,22 @@
+package mpls
+
+import "github.com/NikolayStepanov/RapidVPP/internal/domain"
+
+type InterfaceResponse struct {
+	InterfaceID uint32 `json:"interface_id"`
+}
+
+type TableResponse struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+type RouteResponse struct {
+	Table     uint32           `json:"table"`
+	Label     uint32           `json:"label"`
+	EOS       bool             `json:"eos"`
+	PayloadAF string           `json:"payload_af"`
+	Action    string           `json:"action"`
+	VRF       *uint32          `json:"vrf,omitempty"`
+	Paths     []domain.NextHop `json:"paths,omitempty"`
+}
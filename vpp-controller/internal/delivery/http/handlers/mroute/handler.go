@@ -0,0 +1,122 @@
+package mroute
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	mrouteServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/mroute"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	mroute   service.MRoute
+	resolver service.InterfaceResolver
+}
+
+func NewHandler(mroute service.MRoute, resolver service.InterfaceResolver) *Handler {
+	return &Handler{mroute: mroute, resolver: resolver}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	var vrf uint32
+	if vrfStr := r.URL.Query().Get("vrf"); vrfStr != "" {
+		vrfInt, err := strconv.ParseUint(vrfStr, 10, 32)
+		if err != nil {
+			logger.Warn("Invalid VRF parameter", zap.String("vrf", vrfStr), zap.Error(err))
+			http.Error(w, "Invalid VRF parameter. Must be a number", http.StatusBadRequest)
+			return
+		}
+		vrf = uint32(vrfInt)
+	}
+
+	routes, err := h.mroute.ListMRoutes(r.Context(), vrf)
+	if err != nil {
+		logger.Error("Failed to list multicast routes", zap.Uint32("vrf", vrf), zap.Error(err))
+		http.Error(w, "Failed to list multicast routes", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(MRoutesToResponse(routes)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	var req MRouteRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	route, ok := h.mrouteFromRequest(w, r, &req)
+	if !ok {
+		return
+	}
+
+	if err := h.mroute.AddMRoute(r.Context(), route); err != nil {
+		logger.Error("Failed to add multicast route", zap.Error(err))
+		http.Error(w, "Failed to add multicast route", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req MRouteRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	route, ok := h.mrouteFromRequest(w, r, &req)
+	if !ok {
+		return
+	}
+
+	err := h.mroute.DeleteMRoute(r.Context(), route)
+	switch {
+	case errors.Is(err, mrouteServ.ErrNotFound):
+		logger.Warn("Multicast route not found", zap.Error(err))
+		http.Error(w, "multicast route not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to delete multicast route", zap.Error(err))
+		http.Error(w, "Failed to delete multicast route", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// mrouteFromRequest writes the error response itself when it returns false
+func (h *Handler) mrouteFromRequest(w http.ResponseWriter, r *http.Request, req *MRouteRequest) (domain.MRoute, bool) {
+	ifIndexes := make([]uint32, len(req.Paths))
+	for i, p := range req.Paths {
+		ifIndexes[i] = p.IfIndex
+		if p.Interface == "" {
+			continue
+		}
+		ifIndex, err := h.resolver.ResolveInterface(r.Context(), p.Interface)
+		if err != nil {
+			response.ResolveError(w, "Invalid path interface", err, zap.String("interface", p.Interface))
+			return domain.MRoute{}, false
+		}
+		ifIndexes[i] = ifIndex
+	}
+	route, err := req.ToDomain(ifIndexes)
+	if err != nil {
+		logger.Warn("Invalid multicast route", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.MRoute{}, false
+	}
+	return route, true
+}
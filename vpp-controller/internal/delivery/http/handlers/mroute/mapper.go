@@ -0,0 +1,109 @@
+package mroute
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// ToDomain converts the request, ifIndexes holds the resolved interface of every path
+func (r *MRouteRequest) ToDomain(ifIndexes []uint32) (domain.MRoute, error) {
+	group, err := parseGroup(r.Group)
+	if err != nil {
+		return domain.MRoute{}, err
+	}
+
+	var source net.IP
+	if r.Source != "" {
+		source = net.ParseIP(r.Source)
+		if source == nil {
+			return domain.MRoute{}, fmt.Errorf("invalid source address: %q", r.Source)
+		}
+		full := uint8(128)
+		if source.To4() != nil {
+			full = 32
+		}
+		if group.Prefix != full {
+			return domain.MRoute{}, fmt.Errorf("an (S,G) route needs a single group address, got %q", r.Group)
+		}
+	}
+
+	paths := make([]domain.MRoutePath, 0, len(r.Paths))
+	for i, p := range r.Paths {
+		paths = append(paths, domain.MRoutePath{
+			InterfaceID:   ifIndexes[i],
+			Accept:        p.Accept,
+			Forward:       p.Forward,
+			SignalPresent: p.SignalPresent,
+			NegateSignal:  p.NegateSignal,
+			DontPreserve:  p.DontPreserve,
+		})
+	}
+
+	return domain.MRoute{
+		VRF:                 r.VRF,
+		Source:              source,
+		Group:               group,
+		RPFID:               r.RPFID,
+		Signal:              r.Signal,
+		Drop:                r.Drop,
+		Connected:           r.Connected,
+		AcceptAllInterfaces: r.AcceptAllInterfaces,
+		Paths:               paths,
+	}, nil
+}
+
+// parseGroup accepts a bare group address as a full-length prefix
+func parseGroup(group string) (domain.IPWithPrefix, error) {
+	if !strings.Contains(group, "/") {
+		ip := net.ParseIP(group)
+		if ip == nil {
+			return domain.IPWithPrefix{}, fmt.Errorf("invalid group address: %q", group)
+		}
+		length := 128
+		if ip.To4() != nil {
+			length = 32
+		}
+		return domain.IPWithPrefix{Address: ip.String(), Prefix: uint8(length)}, nil
+	}
+
+	_, ipnet, err := net.ParseCIDR(group)
+	if err != nil {
+		return domain.IPWithPrefix{}, fmt.Errorf("invalid group prefix: %q", group)
+	}
+	ones, _ := ipnet.Mask.Size()
+	return domain.IPWithPrefix{Address: ipnet.IP.String(), Prefix: uint8(ones)}, nil
+}
+
+func MRoutesToResponse(routes []domain.MRoute) []MRouteResponse {
+	res := make([]MRouteResponse, 0, len(routes))
+	for _, route := range routes {
+		resp := MRouteResponse{
+			VRF:                 route.VRF,
+			Group:               fmt.Sprintf("%s/%d", route.Group.Address, route.Group.Prefix),
+			RPFID:               route.RPFID,
+			Signal:              route.Signal,
+			Drop:                route.Drop,
+			Connected:           route.Connected,
+			AcceptAllInterfaces: route.AcceptAllInterfaces,
+			Paths:               make([]MRoutePathResponse, 0, len(route.Paths)),
+		}
+		if route.Source != nil {
+			resp.Source = route.Source.String()
+		}
+		for _, p := range route.Paths {
+			resp.Paths = append(resp.Paths, MRoutePathResponse{
+				InterfaceID:   p.InterfaceID,
+				Accept:        p.Accept,
+				Forward:       p.Forward,
+				SignalPresent: p.SignalPresent,
+				NegateSignal:  p.NegateSignal,
+				DontPreserve:  p.DontPreserve,
+			})
+		}
+		res = append(res, resp)
+	}
+	return res
+}
@@ -0,0 +1,26 @@
+package mroute
+
+type MRouteRequest struct {
+	VRF uint32 `json:"vrf"`
+	// Source is empty for a (*,G) route
+	Source string `json:"source,omitempty"`
+	// Group is a group address or a group prefix such as 232.0.0.0/8
+	Group               string              `json:"group"`
+	RPFID               uint32              `json:"rpf_id,omitempty"`
+	Signal              bool                `json:"signal,omitempty"`
+	Drop                bool                `json:"drop,omitempty"`
+	Connected           bool                `json:"connected,omitempty"`
+	AcceptAllInterfaces bool                `json:"accept_all_interfaces,omitempty"`
+	Paths               []MRoutePathRequest `json:"paths"`
+}
+
+type MRoutePathRequest struct {
+	// Interface is an interface name or index, it takes precedence over IfIndex
+	Interface     string `json:"interface,omitempty"`
+	IfIndex       uint32 `json:"if_index"`
+	Accept        bool   `json:"accept"`
+	Forward       bool   `json:"forward"`
+	SignalPresent bool   `json:"signal_present,omitempty"`
+	NegateSignal  bool   `json:"negate_signal,omitempty"`
+	DontPreserve  bool   `json:"dont_preserve,omitempty"`
+}
@@ -0,0 +1,22 @@
+package mroute
+
+type MRouteResponse struct {
+	VRF                 uint32               `json:"vrf"`
+	Source              string               `json:"source,omitempty"`
+	Group               string               `json:"group"`
+	RPFID               uint32               `json:"rpf_id"`
+	Signal              bool                 `json:"signal"`
+	Drop                bool                 `json:"drop"`
+	Connected           bool                 `json:"connected"`
+	AcceptAllInterfaces bool                 `json:"accept_all_interfaces"`
+	Paths               []MRoutePathResponse `json:"paths"`
+}
+
+type MRoutePathResponse struct {
+	InterfaceID   uint32 `json:"interface_id"`
+	Accept        bool   `json:"accept"`
+	Forward       bool   `json:"forward"`
+	SignalPresent bool   `json:"signal_present"`
+	NegateSignal  bool   `json:"negate_signal"`
+	DontPreserve  bool   `json:"dont_preserve"`
+}
@@ -0,0 +1,227 @@
+package neighbor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	neighborServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/neighbor"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	neighbor service.Neighbor
+	resolver service.InterfaceResolver
+}
+
+func NewHandler(neighbor service.Neighbor, resolver service.InterfaceResolver) *Handler {
+	return &Handler{neighbor: neighbor, resolver: resolver}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.NeighborFilter{
+		InterfaceID: 0xFFFFFFFF,
+		IPv4:        true,
+		IPv6:        true,
+	}
+
+	if ifStr := query.Get("interface"); ifStr != "" {
+		ifIndex, err := h.resolver.ResolveInterface(r.Context(), ifStr)
+		if err != nil {
+			response.ResolveError(w, "Invalid interface parameter", err, zap.String("interface", ifStr))
+			return
+		}
+		filter.InterfaceID = ifIndex
+	}
+
+	if vrfStr := query.Get("vrf"); vrfStr != "" {
+		vrf, err := strconv.ParseUint(vrfStr, 10, 32)
+		if err != nil {
+			logger.Warn("Invalid VRF parameter", zap.String("vrf", vrfStr), zap.Error(err))
+			http.Error(w, "Invalid VRF parameter. Must be a number", http.StatusBadRequest)
+			return
+		}
+		vrfID := uint32(vrf)
+		filter.VRF = &vrfID
+	}
+
+	if afStr := query.Get("af"); afStr != "" {
+		isIPv6, err := ParseAF(afStr)
+		if err != nil {
+			logger.Warn("Invalid af parameter", zap.String("af", afStr), zap.Error(err))
+			http.Error(w, "Invalid af parameter. Use ip4 or ip6", http.StatusBadRequest)
+			return
+		}
+		filter.IPv4 = !isIPv6
+		filter.IPv6 = isIPv6
+	}
+
+	neighbors, err := h.neighbor.ListNeighbors(r.Context(), filter)
+	if err != nil {
+		logger.Error("Failed to list neighbors", zap.Error(err))
+		http.Error(w, "Failed to list neighbors", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(NeighborsToResponse(neighbors)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	var req NeighborRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	neighbor, ok := h.neighborFromRequest(w, r, &req, true)
+	if !ok {
+		return
+	}
+
+	if err := h.neighbor.AddNeighbor(r.Context(), neighbor); err != nil {
+		logger.Error("Failed to add neighbor", zap.Error(err))
+		http.Error(w, "Failed to add neighbor", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req NeighborRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	neighbor, ok := h.neighborFromRequest(w, r, &req, false)
+	if !ok {
+		return
+	}
+
+	err := h.neighbor.DeleteNeighbor(r.Context(), neighbor)
+	switch {
+	case errors.Is(err, neighborServ.ErrNotFound):
+		logger.Warn("Neighbor not found", zap.Error(err))
+		http.Error(w, "neighbor not found", http.StatusNotFound)
+	case err != nil:
+		logger.Error("Failed to delete neighbor", zap.Error(err))
+		http.Error(w, "Failed to delete neighbor", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) Flush(w http.ResponseWriter, r *http.Request) {
+	var req FlushRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	isIPv6, err := ParseAF(req.AF)
+	if err != nil {
+		logger.Warn("Invalid af in request", zap.Error(err))
+		http.Error(w, "Invalid af. Use ip4 or ip6", http.StatusBadRequest)
+		return
+	}
+
+	var ifIndex uint32 = 0xFFFFFFFF
+	if req.Interface != "" {
+		ifIndex, err = h.resolver.ResolveInterface(r.Context(), req.Interface)
+		if err != nil {
+			response.ResolveError(w, "Invalid interface", err, zap.String("interface", req.Interface))
+			return
+		}
+	}
+
+	if err := h.neighbor.FlushNeighbors(r.Context(), ifIndex, isIPv6); err != nil {
+		logger.Error("Failed to flush neighbors", zap.Error(err))
+		http.Error(w, "Failed to flush neighbors", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	afStr := r.URL.Query().Get("af")
+	if afStr == "" {
+		afStr = afIPv4
+	}
+	isIPv6, err := ParseAF(afStr)
+	if err != nil {
+		logger.Warn("Invalid af parameter", zap.String("af", afStr), zap.Error(err))
+		http.Error(w, "Invalid af parameter. Use ip4 or ip6", http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.neighbor.GetNeighborConfig(r.Context(), isIPv6)
+	if err != nil {
+		logger.Error("Failed to get neighbor config", zap.Error(err))
+		http.Error(w, "Failed to get neighbor config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ConfigToResponse(config)); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SetConfig(w http.ResponseWriter, r *http.Request) {
+	var req ConfigRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	config, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid neighbor config", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.neighbor.SetNeighborConfig(r.Context(), config); err != nil {
+		logger.Error("Failed to set neighbor config", zap.Error(err))
+		http.Error(w, "Failed to set neighbor config", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// neighborFromRequest writes the error response itself when it returns false
+func (h *Handler) neighborFromRequest(w http.ResponseWriter, r *http.Request, req *NeighborRequest, requireMAC bool) (domain.Neighbor, bool) {
+	ifIndex := req.IfIndex
+	if req.Interface != "" {
+		var err error
+		ifIndex, err = h.resolver.ResolveInterface(r.Context(), req.Interface)
+		if err != nil {
+			response.ResolveError(w, "Invalid interface", err, zap.String("interface", req.Interface))
+			return domain.Neighbor{}, false
+		}
+	}
+	neighbor, err := req.ToDomain(ifIndex, requireMAC)
+	if err != nil {
+		logger.Warn("Invalid neighbor", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.Neighbor{}, false
+	}
+	return neighbor, true
+}
@@ -0,0 +1,91 @@
+package neighbor
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+const (
+	afIPv4 = "ip4"
+	afIPv6 = "ip6"
+)
+
+func (r *NeighborRequest) ToDomain(ifIndex uint32, requireMAC bool) (domain.Neighbor, error) {
+	ip := net.ParseIP(r.IP)
+	if ip == nil {
+		return domain.Neighbor{}, fmt.Errorf("invalid neighbor IP: %q", r.IP)
+	}
+
+	var mac net.HardwareAddr
+	if r.MAC != "" || requireMAC {
+		var err error
+		mac, err = net.ParseMAC(r.MAC)
+		if err != nil {
+			return domain.Neighbor{}, fmt.Errorf("invalid neighbor MAC: %w", err)
+		}
+	}
+
+	return domain.Neighbor{
+		InterfaceID: ifIndex,
+		IP:          ip,
+		MAC:         mac,
+		Static:      r.Static,
+		NoFIBEntry:  r.NoFIBEntry,
+	}, nil
+}
+
+func (r *ConfigRequest) ToDomain() (domain.NeighborConfig, error) {
+	isIPv6, err := ParseAF(r.AF)
+	if err != nil {
+		return domain.NeighborConfig{}, err
+	}
+
+	return domain.NeighborConfig{
+		IPv6:      isIPv6,
+		MaxNumber: r.MaxNumber,
+		MaxAge:    r.MaxAge,
+		Recycle:   r.Recycle,
+	}, nil
+}
+
+// ParseAF converts ip4/ip6 (or ipv4/ipv6) into an IPv6 flag
+func ParseAF(af string) (bool, error) {
+	switch af {
+	case afIPv4, "ipv4":
+		return false, nil
+	case afIPv6, "ipv6":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid address family: %q", af)
+	}
+}
+
+func NeighborsToResponse(neighbors []domain.Neighbor) []NeighborResponse {
+	res := make([]NeighborResponse, 0, len(neighbors))
+	for _, n := range neighbors {
+		res = append(res, NeighborResponse{
+			InterfaceID: n.InterfaceID,
+			IP:          n.IP.String(),
+			MAC:         n.MAC.String(),
+			Static:      n.Static,
+			NoFIBEntry:  n.NoFIBEntry,
+			Age:         n.Age,
+		})
+	}
+	return res
+}
+
+func ConfigToResponse(config domain.NeighborConfig) ConfigResponse {
+	af := afIPv4
+	if config.IPv6 {
+		af = afIPv6
+	}
+	return ConfigResponse{
+		AF:        af,
+		MaxNumber: config.MaxNumber,
+		MaxAge:    config.MaxAge,
+		Recycle:   config.Recycle,
+	}
+}
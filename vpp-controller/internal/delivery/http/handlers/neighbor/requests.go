@@ -0,0 +1,24 @@
+package neighbor
+
+type NeighborRequest struct {
+	// Interface is an interface name or index, it takes precedence over IfIndex
+	Interface  string `json:"interface,omitempty"`
+	IfIndex    uint32 `json:"if_index"`
+	IP         string `json:"ip"`
+	MAC        string `json:"mac,omitempty"`
+	Static     bool   `json:"static"`
+	NoFIBEntry bool   `json:"no_fib_entry"`
+}
+
+type FlushRequest struct {
+	// Interface is an interface name or index, empty flushes all interfaces
+	Interface string `json:"interface,omitempty"`
+	AF        string `json:"af"`
+}
+
+type ConfigRequest struct {
+	AF        string `json:"af"`
+	MaxNumber uint32 `json:"max_number"`
+	MaxAge    uint32 `json:"max_age"`
+	Recycle   bool   `json:"recycle"`
+}
@@ -0,0 +1,17 @@
+package neighbor
+
+type NeighborResponse struct {
+	InterfaceID uint32  `json:"interface_id"`
+	IP          string  `json:"ip"`
+	MAC         string  `json:"mac"`
+	Static      bool    `json:"static"`
+	NoFIBEntry  bool    `json:"no_fib_entry"`
+	Age         float64 `json:"age"`
+}
+
+type ConfigResponse struct {
+	AF        string `json:"af"`
+	MaxNumber uint32 `json:"max_number"`
+	MaxAge    uint32 `json:"max_age"`
+	Recycle   bool   `json:"recycle"`
+}
@@ -0,0 +1,34 @@
+// Package response holds the replies shared by the HTTP handlers
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	interfacesServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/interfaces"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ResolveError answers 400 when the request names no interface or an unknown
+// one and 500 when the interfaces could not be read from VPP
+func ResolveError(w http.ResponseWriter, msg string, err error, fields ...zap.Field) {
+	fields = append(fields, zap.Error(err))
+	if errors.Is(err, interfacesServ.ErrNotFound) || errors.Is(err, interfacesServ.ErrInvalid) {
+		logger.Warn(msg, fields...)
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	logger.Error("Failed to resolve interface", fields...)
+	http.Error(w, "Failed to resolve interface", http.StatusInternalServerError)
+}
+
+// JSON writes v as the JSON body of a 200 response
+func JSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error("Failed to encode response", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -8,22 +8,116 @@ func (h *Handler) setupRoutes() {
 	h.router.HandleFunc("POST /interfaces/{id}/state", h.interfaceHandler.SetInterfaceState)
 	h.router.HandleFunc("POST /interfaces/{id}/ip", h.interfaceHandler.AddInterfaceIP)
 	h.router.HandleFunc("DELETE /interfaces/{id}", h.interfaceHandler.DeleteLoopback)
+	h.router.HandleFunc("PUT /interfaces/{id}/tag", h.interfaceHandler.SetTag)
+	h.router.HandleFunc("GET /interfaces/{id}/metadata", h.interfaceHandler.GetMetadata)
+	h.router.HandleFunc("PUT /interfaces/{id}/metadata", h.interfaceHandler.SetMetadata)
+	h.router.HandleFunc("DELETE /interfaces/{id}/metadata", h.interfaceHandler.DeleteMetadata)
+	h.router.HandleFunc("GET /interfaces/{id}/urpf", h.interfaceHandler.GetURPF)
+	h.router.HandleFunc("PUT /interfaces/{id}/urpf", h.interfaceHandler.SetURPF)
 
 	h.router.HandleFunc("GET /interfaces/{id}/acl", h.interfaceHandler.ListACL)
 	h.router.HandleFunc("POST /interfaces/{id}/acl", h.interfaceHandler.AttachACL)
+	h.router.HandleFunc("PUT /interfaces/{id}/acl", h.interfaceHandler.SetACLList)
 	h.router.HandleFunc("DELETE /interfaces/{id}/acl", h.interfaceHandler.DetachACL)
 
+	h.router.HandleFunc("GET /interfaces/unnumbered", h.interfaceHandler.ListUnnumbered)
+	h.router.HandleFunc("PUT /interfaces/{id}/unnumbered", h.interfaceHandler.SetUnnumbered)
+	h.router.HandleFunc("DELETE /interfaces/{id}/unnumbered", h.interfaceHandler.DeleteUnnumbered)
+
+	h.router.HandleFunc("PUT /interfaces/{id}/ip6", h.ip6ndHandler.EnableIPv6)
+	h.router.HandleFunc("GET /interfaces/{id}/ip6/ra", h.ip6ndHandler.GetRA)
+	h.router.HandleFunc("PUT /interfaces/{id}/ip6/ra", h.ip6ndHandler.SetRA)
+	h.router.HandleFunc("POST /interfaces/{id}/ip6/ra/prefixes", h.ip6ndHandler.AddRAPrefix)
+	h.router.HandleFunc("DELETE /interfaces/{id}/ip6/ra/prefixes", h.ip6ndHandler.DeleteRAPrefix)
+	h.router.HandleFunc("GET /ip6/ra", h.ip6ndHandler.ListRA)
+
 	h.router.HandleFunc("GET /routes", h.ipHandler.List)
 	h.router.HandleFunc("GET /routes/{vrf}", h.ipHandler.Get)
 	h.router.HandleFunc("POST /routes", h.ipHandler.AddRoute)
 	h.router.HandleFunc("DELETE /routes", h.ipHandler.DeleteRoute)
+	h.router.HandleFunc("PUT /routes", h.ipHandler.ReplaceRoute)
+	h.router.HandleFunc("PATCH /routes", h.ipHandler.PatchRoute)
+	h.router.HandleFunc("DELETE /routes/{vrf}", h.ipHandler.DeleteRoutePrefix)
 
 	h.router.HandleFunc("GET /vrf", h.ipHandler.ListVRF)
 	h.router.HandleFunc("POST /vrf", h.ipHandler.CreateVRF)
 	h.router.HandleFunc("DELETE /vrf/{id}", h.ipHandler.DeleteVRF)
+	h.router.HandleFunc("GET /vrf/{vrf}/lookup", h.ipHandler.Lookup)
+	h.router.HandleFunc("GET /vrf/{id}/flow-hash", h.ipHandler.GetFlowHash)
+	h.router.HandleFunc("PUT /vrf/{id}/flow-hash", h.ipHandler.SetFlowHash)
+	h.router.HandleFunc("GET /vrf/{id}/leaks", h.ipHandler.ListVRFLeaks)
+	h.router.HandleFunc("POST /vrf/{id}/leaks", h.ipHandler.CreateVRFLeak)
+	h.router.HandleFunc("DELETE /vrf/{id}/leaks/{leak}", h.ipHandler.DeleteVRFLeak)
+
+	h.router.HandleFunc("GET /mroutes", h.mrouteHandler.List)
+	h.router.HandleFunc("POST /mroutes", h.mrouteHandler.Add)
+	h.router.HandleFunc("DELETE /mroutes", h.mrouteHandler.Delete)
+
+	h.router.HandleFunc("PUT /interfaces/{id}/mpls", h.mplsHandler.EnableInterface)
+	h.router.HandleFunc("GET /mpls/interfaces", h.mplsHandler.ListInterfaces)
+	h.router.HandleFunc("GET /mpls/tables", h.mplsHandler.ListTables)
+	h.router.HandleFunc("POST /mpls/tables", h.mplsHandler.CreateTable)
+	h.router.HandleFunc("DELETE /mpls/tables/{id}", h.mplsHandler.DeleteTable)
+	h.router.HandleFunc("GET /mpls/tables/{id}/routes", h.mplsHandler.ListRoutes)
+	h.router.HandleFunc("POST /mpls/routes", h.mplsHandler.AddRoute)
+	h.router.HandleFunc("DELETE /mpls/tables/{id}/routes/{label}", h.mplsHandler.DeleteRoute)
+	h.router.HandleFunc("GET /srv6/localsids", h.srv6Handler.ListLocalSIDs)
+	h.router.HandleFunc("POST /srv6/localsids", h.srv6Handler.AddLocalSID)
+	h.router.HandleFunc("DELETE /srv6/localsids/{sid}", h.srv6Handler.DeleteLocalSID)
+	h.router.HandleFunc("GET /srv6/policies", h.srv6Handler.ListPolicies)
+	h.router.HandleFunc("POST /srv6/policies", h.srv6Handler.CreatePolicy)
+	h.router.HandleFunc("GET /srv6/policies/{bsid}", h.srv6Handler.GetPolicy)
+	h.router.HandleFunc("DELETE /srv6/policies/{bsid}", h.srv6Handler.DeletePolicy)
+	h.router.HandleFunc("POST /srv6/policies/{bsid}/segment-lists", h.srv6Handler.AddSegmentList)
+	h.router.HandleFunc("DELETE /srv6/policies/{bsid}/segment-lists/{index}", h.srv6Handler.DeleteSegmentList)
+	h.router.HandleFunc("GET /srv6/steering", h.srv6Handler.ListSteering)
+	h.router.HandleFunc("POST /srv6/steering", h.srv6Handler.AddSteering)
+	h.router.HandleFunc("DELETE /srv6/steering", h.srv6Handler.DeleteSteering)
+	h.router.HandleFunc("PUT /srv6/encap-source", h.srv6Handler.SetEncapSource)
+	h.router.HandleFunc("GET /abf/policies", h.abfHandler.ListPolicies)
+	h.router.HandleFunc("POST /abf/policies", h.abfHandler.CreatePolicy)
+	h.router.HandleFunc("GET /abf/policies/{id}", h.abfHandler.GetPolicy)
+	h.router.HandleFunc("DELETE /abf/policies/{id}", h.abfHandler.DeletePolicy)
+	h.router.HandleFunc("GET /abf/attachments", h.abfHandler.ListAttachments)
+	h.router.HandleFunc("POST /interfaces/{id}/abf", h.abfHandler.Attach)
+	h.router.HandleFunc("DELETE /interfaces/{id}/abf/{policy}", h.abfHandler.Detach)
+
+	h.router.HandleFunc("GET /neighbors", h.neighborHandler.List)
+	h.router.HandleFunc("POST /neighbors", h.neighborHandler.Add)
+	h.router.HandleFunc("DELETE /neighbors", h.neighborHandler.Delete)
+	h.router.HandleFunc("POST /neighbors/flush", h.neighborHandler.Flush)
+	h.router.HandleFunc("GET /neighbors/config", h.neighborHandler.GetConfig)
+	h.router.HandleFunc("PUT /neighbors/config", h.neighborHandler.SetConfig)
 
 	h.router.HandleFunc("GET /acl", h.aclHandler.List)
 	h.router.HandleFunc("POST /acl", h.aclHandler.Create)
+	h.router.HandleFunc("POST /acl/import", h.aclHandler.Import)
+	h.router.HandleFunc("POST /acl/simulate", h.aclHandler.Simulate)
 	h.router.HandleFunc("PUT /acl/{id}", h.aclHandler.Update)
 	h.router.HandleFunc("DELETE /acl/{id}", h.aclHandler.Delete)
+	h.router.HandleFunc("GET /acl/{id}/analysis", h.aclHandler.Analyze)
+	h.router.HandleFunc("GET /acl/{id}/stats", h.aclHandler.GetStats)
+	h.router.HandleFunc("DELETE /acl/{id}/stats", h.aclHandler.ResetStats)
+	h.router.HandleFunc("GET /interfaces/{id}/acl/sessions", h.aclHandler.GetSessionStats)
+	h.router.HandleFunc("DELETE /interfaces/{id}/acl/sessions", h.aclHandler.ClearInterfaceSessions)
+	h.router.HandleFunc("DELETE /acl/sessions", h.aclHandler.ClearSessions)
+	h.router.HandleFunc("GET /acl/sessions/timeouts", h.aclHandler.GetSessionTimeouts)
+	h.router.HandleFunc("PUT /acl/sessions/timeouts", h.aclHandler.SetSessionTimeouts)
+
+	h.router.HandleFunc("GET /acl/groups/address", h.aclGroupHandler.ListAddressGroups)
+	h.router.HandleFunc("GET /acl/groups/address/{name}", h.aclGroupHandler.GetAddressGroup)
+	h.router.HandleFunc("PUT /acl/groups/address/{name}", h.aclGroupHandler.SetAddressGroup)
+	h.router.HandleFunc("DELETE /acl/groups/address/{name}", h.aclGroupHandler.DeleteAddressGroup)
+	h.router.HandleFunc("GET /acl/groups/port", h.aclGroupHandler.ListPortGroups)
+	h.router.HandleFunc("GET /acl/groups/port/{name}", h.aclGroupHandler.GetPortGroup)
+	h.router.HandleFunc("PUT /acl/groups/port/{name}", h.aclGroupHandler.SetPortGroup)
+	h.router.HandleFunc("DELETE /acl/groups/port/{name}", h.aclGroupHandler.DeletePortGroup)
+
+	h.router.HandleFunc("GET /macip-acl", h.macipHandler.List)
+	h.router.HandleFunc("POST /macip-acl", h.macipHandler.Create)
+	h.router.HandleFunc("PUT /macip-acl/{id}", h.macipHandler.Replace)
+	h.router.HandleFunc("DELETE /macip-acl/{id}", h.macipHandler.Delete)
+	h.router.HandleFunc("GET /macip-acl/interfaces", h.macipHandler.ListAssignments)
+	h.router.HandleFunc("PUT /interfaces/{id}/macip-acl", h.macipHandler.Assign)
+	h.router.HandleFunc("DELETE /interfaces/{id}/macip-acl", h.macipHandler.Unassign)
 }
@@ -0,0 +1,291 @@
+package srv6
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/delivery/http/handlers/response"
+	"github.com/NikolayStepanov/RapidVPP/internal/service"
+	srv6Serv "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/srv6"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	srv6     service.SRv6
+	resolver service.InterfaceResolver
+}
+
+func NewHandler(srv6 service.SRv6, resolver service.InterfaceResolver) *Handler {
+	return &Handler{srv6: srv6, resolver: resolver}
+}
+
+func (h *Handler) ListLocalSIDs(w http.ResponseWriter, r *http.Request) {
+	sids, err := h.srv6.ListLocalSIDs(r.Context())
+	if err != nil {
+		logger.Error("Failed to list local SIDs", zap.Error(err))
+		http.Error(w, "Failed to list local SIDs", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, LocalSIDsToResponse(sids))
+}
+
+func (h *Handler) AddLocalSID(w http.ResponseWriter, r *http.Request) {
+	var req LocalSIDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ifIndex := req.IfIndex
+	if req.Interface != "" {
+		var err error
+		ifIndex, err = h.resolver.ResolveInterface(r.Context(), req.Interface)
+		if err != nil {
+			response.ResolveError(w, "Invalid interface", err, zap.String("interface", req.Interface))
+			return
+		}
+	}
+
+	sid, err := req.ToDomain(ifIndex)
+	if err != nil {
+		logger.Warn("Invalid local SID", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.srv6.AddLocalSID(r.Context(), sid)
+	if writeServiceError(w, err, "Failed to add local SID") {
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) DeleteLocalSID(w http.ResponseWriter, r *http.Request) {
+	sid, ok := parseIPv6PathValue(w, r, "sid")
+	if !ok {
+		return
+	}
+	var table uint64
+	if tableStr := r.URL.Query().Get("table"); tableStr != "" {
+		var err error
+		table, err = strconv.ParseUint(tableStr, 10, 32)
+		if err != nil {
+			logger.Warn("Invalid table parameter", zap.String("table", tableStr), zap.Error(err))
+			http.Error(w, "Invalid table parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := h.srv6.DeleteLocalSID(r.Context(), sid, uint32(table))
+	if writeServiceError(w, err, "Failed to delete local SID") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.srv6.ListPolicies(r.Context())
+	if err != nil {
+		logger.Error("Failed to list SR policies", zap.Error(err))
+		http.Error(w, "Failed to list SR policies", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, PoliciesToResponse(policies))
+}
+
+func (h *Handler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	bsid, ok := parseIPv6PathValue(w, r, "bsid")
+	if !ok {
+		return
+	}
+
+	policy, err := h.srv6.GetPolicy(r.Context(), bsid)
+	if writeServiceError(w, err, "Failed to get SR policy") {
+		return
+	}
+	response.JSON(w, PolicyToResponse(policy))
+}
+
+func (h *Handler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req PolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	policy, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid SR policy", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.srv6.CreatePolicy(r.Context(), policy)
+	if writeServiceError(w, err, "Failed to create SR policy") {
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	bsid, ok := parseIPv6PathValue(w, r, "bsid")
+	if !ok {
+		return
+	}
+
+	err := h.srv6.DeletePolicy(r.Context(), bsid)
+	if writeServiceError(w, err, "Failed to delete SR policy") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) AddSegmentList(w http.ResponseWriter, r *http.Request) {
+	bsid, ok := parseIPv6PathValue(w, r, "bsid")
+	if !ok {
+		return
+	}
+	var req SegmentListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	list, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid segment list", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.srv6.AddSegmentList(r.Context(), bsid, list)
+	if writeServiceError(w, err, "Failed to add segment list") {
+		return
+	}
+	response.JSON(w, PolicyToResponse(policy))
+}
+
+func (h *Handler) DeleteSegmentList(w http.ResponseWriter, r *http.Request) {
+	bsid, ok := parseIPv6PathValue(w, r, "bsid")
+	if !ok {
+		return
+	}
+	indexStr := r.PathValue("index")
+	index, err := strconv.ParseUint(indexStr, 10, 32)
+	if err != nil {
+		logger.Warn("Invalid segment list index", zap.String("index", indexStr), zap.Error(err))
+		http.Error(w, "Invalid segment list index", http.StatusBadRequest)
+		return
+	}
+
+	err = h.srv6.DeleteSegmentList(r.Context(), bsid, uint32(index))
+	if writeServiceError(w, err, "Failed to delete segment list") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListSteering(w http.ResponseWriter, r *http.Request) {
+	steering, err := h.srv6.ListSteering(r.Context())
+	if err != nil {
+		logger.Error("Failed to list SR steering", zap.Error(err))
+		http.Error(w, "Failed to list SR steering", http.StatusInternalServerError)
+		return
+	}
+	response.JSON(w, SteeringToResponse(steering))
+}
+
+func (h *Handler) AddSteering(w http.ResponseWriter, r *http.Request) {
+	h.addDelSteering(w, r, false)
+}
+
+func (h *Handler) DeleteSteering(w http.ResponseWriter, r *http.Request) {
+	h.addDelSteering(w, r, true)
+}
+
+func (h *Handler) addDelSteering(w http.ResponseWriter, r *http.Request, isDel bool) {
+	var req SteeringRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	steering, err := req.ToDomain()
+	if err != nil {
+		logger.Warn("Invalid SR steering", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if isDel {
+		err = h.srv6.DeleteSteering(r.Context(), steering)
+		if writeServiceError(w, err, "Failed to delete SR steering") {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	err = h.srv6.AddSteering(r.Context(), steering)
+	if writeServiceError(w, err, "Failed to add SR steering") {
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) SetEncapSource(w http.ResponseWriter, r *http.Request) {
+	var req EncapSourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("Invalid request body", zap.Error(err))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	addr, err := parseIPv6(req.Address)
+	if err != nil {
+		logger.Warn("Invalid encap source", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.srv6.SetEncapSource(r.Context(), addr)
+	if writeServiceError(w, err, "Failed to set SR encap source") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeServiceError maps a service error to a response and reports whether one was written
+func writeServiceError(w http.ResponseWriter, err error, msg string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, srv6Serv.ErrInvalid):
+		logger.Warn(msg, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, srv6Serv.ErrVRFNotFound), errors.Is(err, srv6Serv.ErrNotFound):
+		logger.Warn(msg, zap.Error(err))
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, srv6Serv.ErrLastSegmentList):
+		logger.Warn(msg, zap.Error(err))
+		http.Error(w, "Policy must keep at least one segment list, delete the policy instead", http.StatusConflict)
+	default:
+		logger.Error(msg, zap.Error(err))
+		http.Error(w, msg, http.StatusInternalServerError)
+	}
+	return true
+}
+
+func parseIPv6PathValue(w http.ResponseWriter, r *http.Request, name string) (net.IP, bool) {
+	value := r.PathValue(name)
+	ip, err := parseIPv6(value)
+	if err != nil {
+		logger.Warn("Invalid IPv6 address in path", zap.String(name, value), zap.Error(err))
+		http.Error(w, "Invalid "+name, http.StatusBadRequest)
+		return nil, false
+	}
+	return ip, true
+}
@@ -0,0 +1,181 @@
+package srv6
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// ToDomain converts the request, ifIndex is the resolved End.X interface
+func (r *LocalSIDRequest) ToDomain(ifIndex uint32) (domain.SRv6LocalSID, error) {
+	sid, err := parseIPv6(r.SID)
+	if err != nil {
+		return domain.SRv6LocalSID{}, err
+	}
+	behavior, err := domain.ParseSRv6Behavior(r.Behavior)
+	if err != nil {
+		return domain.SRv6LocalSID{}, err
+	}
+
+	localSID := domain.SRv6LocalSID{
+		SID:      sid,
+		Behavior: behavior,
+		PSP:      r.PSP,
+		Table:    r.Table,
+	}
+	switch {
+	case behavior == domain.SRv6EndX:
+		nextHop := net.ParseIP(r.NextHop)
+		if nextHop == nil {
+			return domain.SRv6LocalSID{}, fmt.Errorf("End.X requires a valid next_hop, got %q", r.NextHop)
+		}
+		localSID.InterfaceID = ifIndex
+		localSID.NextHop = nextHop
+	case behavior.IsDecap():
+		localSID.VRF = r.VRF
+	}
+	return localSID, nil
+}
+
+func (r *PolicyRequest) ToDomain() (domain.SRv6Policy, error) {
+	bsid, err := parseIPv6(r.BSID)
+	if err != nil {
+		return domain.SRv6Policy{}, err
+	}
+	if len(r.SegmentLists) == 0 {
+		return domain.SRv6Policy{}, fmt.Errorf("policy requires at least one segment list")
+	}
+
+	lists := make([]domain.SRv6SegmentList, 0, len(r.SegmentLists))
+	for _, l := range r.SegmentLists {
+		list, err := l.ToDomain()
+		if err != nil {
+			return domain.SRv6Policy{}, err
+		}
+		lists = append(lists, list)
+	}
+
+	return domain.SRv6Policy{
+		BSID:         bsid,
+		Encap:        r.Encap,
+		Spray:        r.Spray,
+		Table:        r.Table,
+		SegmentLists: lists,
+	}, nil
+}
+
+func (r *SegmentListRequest) ToDomain() (domain.SRv6SegmentList, error) {
+	if len(r.Segments) == 0 {
+		return domain.SRv6SegmentList{}, fmt.Errorf("segment list is empty")
+	}
+	if len(r.Segments) > domain.MaxSegments {
+		return domain.SRv6SegmentList{}, fmt.Errorf("segment list exceeds %d segments", domain.MaxSegments)
+	}
+
+	segments := make([]net.IP, 0, len(r.Segments))
+	for _, s := range r.Segments {
+		segment, err := parseIPv6(s)
+		if err != nil {
+			return domain.SRv6SegmentList{}, err
+		}
+		segments = append(segments, segment)
+	}
+
+	weight := r.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	return domain.SRv6SegmentList{Weight: weight, Segments: segments}, nil
+}
+
+func (r *SteeringRequest) ToDomain() (domain.SRv6Steering, error) {
+	bsid, err := parseIPv6(r.BSID)
+	if err != nil {
+		return domain.SRv6Steering{}, err
+	}
+
+	steering := domain.SRv6Steering{BSID: bsid, VRF: r.VRF}
+	if r.Prefix != "" {
+		_, ipnet, err := net.ParseCIDR(r.Prefix)
+		if err != nil {
+			return domain.SRv6Steering{}, fmt.Errorf("invalid prefix: %q", r.Prefix)
+		}
+		ones, _ := ipnet.Mask.Size()
+		steering.Prefix = domain.IPWithPrefix{Address: ipnet.IP.String(), Prefix: uint8(ones)}
+	}
+	return steering, nil
+}
+
+func parseIPv6(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil || ip.To4() != nil {
+		return nil, fmt.Errorf("invalid IPv6 address: %q", s)
+	}
+	return ip, nil
+}
+
+func LocalSIDsToResponse(sids []domain.SRv6LocalSID) []LocalSIDResponse {
+	res := make([]LocalSIDResponse, 0, len(sids))
+	for _, sid := range sids {
+		resp := LocalSIDResponse{
+			SID:      sid.SID.String(),
+			Behavior: sid.Behavior.String(),
+			PSP:      sid.PSP,
+			Table:    sid.Table,
+		}
+		switch {
+		case sid.Behavior == domain.SRv6EndX:
+			ifIndex := sid.InterfaceID
+			resp.InterfaceID = &ifIndex
+			resp.NextHop = sid.NextHop.String()
+		case sid.Behavior.IsDecap():
+			vrf := sid.VRF
+			resp.VRF = &vrf
+		}
+		res = append(res, resp)
+	}
+	return res
+}
+
+func PolicyToResponse(policy domain.SRv6Policy) PolicyResponse {
+	lists := make([]SegmentListResponse, 0, len(policy.SegmentLists))
+	for _, list := range policy.SegmentLists {
+		segments := make([]string, 0, len(list.Segments))
+		for _, segment := range list.Segments {
+			segments = append(segments, segment.String())
+		}
+		lists = append(lists, SegmentListResponse{
+			Index:    list.Index,
+			Weight:   list.Weight,
+			Segments: segments,
+		})
+	}
+	return PolicyResponse{
+		BSID:         policy.BSID.String(),
+		Encap:        policy.Encap,
+		Spray:        policy.Spray,
+		Table:        policy.Table,
+		SegmentLists: lists,
+	}
+}
+
+func PoliciesToResponse(policies []domain.SRv6Policy) []PolicyResponse {
+	res := make([]PolicyResponse, 0, len(policies))
+	for _, policy := range policies {
+		res = append(res, PolicyToResponse(policy))
+	}
+	return res
+}
+
+func SteeringToResponse(steering []domain.SRv6Steering) []SteeringResponse {
+	res := make([]SteeringResponse, 0, len(steering))
+	for _, s := range steering {
+		res = append(res, SteeringResponse{
+			BSID:   s.BSID.String(),
+			VRF:    s.VRF,
+			Prefix: fmt.Sprintf("%s/%d", s.Prefix.Address, s.Prefix.Prefix),
+		})
+	}
+	return res
+}
@@ -0,0 +1,40 @@
+package srv6
+
+type LocalSIDRequest struct {
+	SID string `json:"sid"`
+	// Behavior is End, End.X, End.DT4, End.DT6 or End.DT46
+	Behavior string `json:"behavior"`
+	PSP      bool   `json:"psp,omitempty"`
+	// Table is the FIB table the SID is installed in
+	Table uint32 `json:"table,omitempty"`
+	// VRF is the lookup table of End.DT* behaviors, it must exist
+	VRF uint32 `json:"vrf,omitempty"`
+	// Interface is an interface name or index, it takes precedence over IfIndex
+	Interface string `json:"interface,omitempty"`
+	IfIndex   uint32 `json:"if_index,omitempty"`
+	NextHop   string `json:"next_hop,omitempty"`
+}
+
+type PolicyRequest struct {
+	BSID         string               `json:"bsid"`
+	Encap        bool                 `json:"encap"`
+	Spray        bool                 `json:"spray,omitempty"`
+	Table        uint32               `json:"table,omitempty"`
+	SegmentLists []SegmentListRequest `json:"segment_lists"`
+}
+
+type SegmentListRequest struct {
+	Weight   uint32   `json:"weight,omitempty"`
+	Segments []string `json:"segments"`
+}
+
+type SteeringRequest struct {
+	BSID string `json:"bsid"`
+	VRF  uint32 `json:"vrf"`
+	// Prefix is empty to steer all traffic of the VRF
+	Prefix string `json:"prefix,omitempty"`
+}
+
+type EncapSourceRequest struct {
+	Address string `json:"address"`
+}
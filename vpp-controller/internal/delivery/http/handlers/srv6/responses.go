@@ -0,0 +1,31 @@
+package srv6
+
+type LocalSIDResponse struct {
+	SID         string  `json:"sid"`
+	Behavior    string  `json:"behavior"`
+	PSP         bool    `json:"psp"`
+	Table       uint32  `json:"table"`
+	VRF         *uint32 `json:"vrf,omitempty"`
+	InterfaceID *uint32 `json:"interface_id,omitempty"`
+	NextHop     string  `json:"next_hop,omitempty"`
+}
+
+type PolicyResponse struct {
+	BSID         string                `json:"bsid"`
+	Encap        bool                  `json:"encap"`
+	Spray        bool                  `json:"spray"`
+	Table        uint32                `json:"table"`
+	SegmentLists []SegmentListResponse `json:"segment_lists"`
+}
+
+type SegmentListResponse struct {
+	Index    uint32   `json:"index"`
+	Weight   uint32   `json:"weight"`
+	Segments []string `json:"segments"`
+}
+
+type SteeringResponse struct {
+	BSID   string `json:"bsid"`
+	VRF    uint32 `json:"vrf"`
+	Prefix string `json:"prefix"`
+}
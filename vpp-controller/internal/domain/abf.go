@@ -0,0 +1,38 @@
+package domain
+
+// ABFPolicy forwards packets matching the permit rules of an ACL over
+// its paths instead of the FIB lookup result
+type ABFPolicy struct {
+	ID       uint32
+	ACLID    AclID
+	NextHops []NextHop
+}
+
+// ABFAttachment applies a policy to the input of an interface, attachments
+// of one address family are evaluated in ascending priority order
+type ABFAttachment struct {
+	PolicyID    uint32
+	InterfaceID uint32
+	Priority    uint32
+	IPv6        bool
+}
+
+// ABFAttachmentFilter narrows an attachment listing, nil fields match all
+type ABFAttachmentFilter struct {
+	InterfaceID *uint32
+	PolicyID    *uint32
+	IPv6        *bool
+}
+
+func (f ABFAttachmentFilter) Match(a ABFAttachment) bool {
+	if f.InterfaceID != nil && *f.InterfaceID != a.InterfaceID {
+		return false
+	}
+	if f.PolicyID != nil && *f.PolicyID != a.PolicyID {
+		return false
+	}
+	if f.IPv6 != nil && *f.IPv6 != a.IPv6 {
+		return false
+	}
+	return true
+}
@@ -1,13 +1,48 @@
 package domain
 
+import (
+	"fmt"
+	"net"
+)
+
 type ACLAction uint8
 type AclID uint32
 
 const (
 	ACLDeny   ACLAction = 0
 	ACLPermit ACLAction = 1
+	// ACLPermitReflect permits the packet and creates a session that
+	// permits the return traffic on the same interface
+	ACLPermitReflect ACLAction = 2
 )
 
+var aclActionNames = map[ACLAction]string{
+	ACLDeny:          "deny",
+	ACLPermit:        "permit",
+	ACLPermitReflect: "reflect",
+}
+
+func (a ACLAction) String() string {
+	if name, ok := aclActionNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("ACLAction(%d)", uint8(a))
+}
+
+func (a ACLAction) Valid() bool {
+	_, ok := aclActionNames[a]
+	return ok
+}
+
+func ParseACLAction(s string) (ACLAction, error) {
+	for action, name := range aclActionNames {
+		if name == s {
+			return action, nil
+		}
+	}
+	return ACLDeny, fmt.Errorf("unknown acl action: %q", s)
+}
+
 type ACLRule struct {
 	Action        ACLAction
 	Proto         uint8
@@ -26,3 +61,148 @@ type ACLInfo struct {
 	Name  string
 	Rules []ACLRule
 }
+
+// ACLSessionStats counts the reflexive sessions created on an interface
+// across all worker threads
+type ACLSessionStats struct {
+	InterfaceID uint32
+	Added       uint64
+	Deleted     uint64
+	Active      uint64
+}
+
+// ACLSessionTimeouts are the idle timeouts of reflexive sessions in seconds
+type ACLSessionTimeouts struct {
+	UDPIdle      uint32
+	TCPIdle      uint32
+	TCPTransient uint32
+}
+
+// MACIPRule matches the source MAC and source IP of packets, rules only
+// permit or deny
+type MACIPRule struct {
+	Action  ACLAction
+	MAC     net.HardwareAddr
+	MACMask net.HardwareAddr
+	Src     IPWithPrefix
+}
+
+type MACIPACL struct {
+	ID    AclID
+	Name  string
+	Rules []MACIPRule
+}
+
+// MACIPAssignment is the MACIP ACL applied to the input of an interface,
+// an interface has at most one
+type MACIPAssignment struct {
+	InterfaceID uint32
+	ACLID       AclID
+}
+
+// ACLChain is a firewall chain translated into the rules of one ACL
+type ACLChain struct {
+	Name  string
+	Rules []ACLRule
+}
+
+// ACLImportIssue is a firewall rule left out of an import because it uses
+// matches or targets that VPP ACLs cannot express. Deny marks a left out
+// DROP or REJECT rule, the imported ACLs then permit traffic it dropped.
+type ACLImportIssue struct {
+	Chain  string
+	Rule   string
+	Reason string
+	Deny   bool
+}
+
+type PortRange struct {
+	Low  uint16
+	High uint16
+}
+
+// ACLAddressGroup is a named set of prefixes ACL rules can match on
+type ACLAddressGroup struct {
+	Name     string
+	Prefixes []IPWithPrefix
+}
+
+// ACLPortGroup is a named set of port ranges ACL rules can match on
+type ACLPortGroup struct {
+	Name  string
+	Ports []PortRange
+}
+
+// ACLGroupRule is an ACL rule whose addresses and ports may name object
+// groups, a group reference replaces the field and expands into one rule per
+// group member
+type ACLGroupRule struct {
+	ACLRule
+	SrcGroup     string
+	DstGroup     string
+	SrcPortGroup string
+	DstPortGroup string
+}
+
+// HasGroups reports whether the rule references any object group
+func (r ACLGroupRule) HasGroups() bool {
+	return r.SrcGroup != "" || r.DstGroup != "" || r.SrcPortGroup != "" || r.DstPortGroup != ""
+}
+
+type ACLFindingKind string
+
+const (
+	// ACLFindingShadowed is a rule never matched because an earlier rule
+	// with another action matches all its packets
+	ACLFindingShadowed ACLFindingKind = "shadowed"
+	// ACLFindingRedundant is a rule that can be removed without changing
+	// the verdict of any packet
+	ACLFindingRedundant ACLFindingKind = "redundant"
+	// ACLFindingConflict is a rule partly overlapping a rule with the
+	// opposite permit or deny verdict
+	ACLFindingConflict ACLFindingKind = "conflict"
+)
+
+// ACLFinding refers to rules by their position in the ACL, Related is the
+// rule causing the finding
+type ACLFinding struct {
+	Kind    ACLFindingKind
+	Rule    int
+	Related int
+}
+
+// ACLPacket is a packet checked against ACLs, for ICMP the ports hold the
+// type and code
+type ACLPacket struct {
+	Src      net.IP
+	Dst      net.IP
+	Proto    uint8
+	SrcPort  uint16
+	DstPort  uint16
+	TCPFlags uint8
+}
+
+// ACLSimulation is the outcome of checking a packet against the ACLs of an
+// interface direction, ACLs are evaluated in order until a rule matches
+type ACLSimulation struct {
+	Evaluated []ACLInfo
+	Matched   bool
+	ACLID     AclID
+	RuleIndex int
+	// Action is the verdict: the action of the matching rule, deny when
+	// ACLs are applied but none matched and permit when none are applied
+	Action ACLAction
+}
+
+// ACLRuleCounter counts the packets and bytes matched by a rule across all
+// worker threads since the last reset
+type ACLRuleCounter struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// ACLStats holds the counters of an ACL in rule order
+type ACLStats struct {
+	ID    AclID
+	Rules []ACLRuleCounter
+}
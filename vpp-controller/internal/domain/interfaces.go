@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"fmt"
 	"net"
+	"net/netip"
 )
 
 type IPWithPrefix struct {
@@ -13,9 +15,102 @@ func (ip IPWithPrefix) ToNetIP() net.IP {
 	return net.ParseIP(ip.Address)
 }
 
+func (ip IPWithPrefix) IsIPv6() bool {
+	addr := ip.ToNetIP()
+	return addr != nil && addr.To4() == nil
+}
+
+// NetipPrefix returns the address and length as a masked netip.Prefix
+func (ip IPWithPrefix) NetipPrefix() (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(ip.Address)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	prefix, err := addr.Unmap().Prefix(int(ip.Prefix))
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return prefix, nil
+}
+
 type ACLInterfaceList struct {
 	InterfaceID uint32
 	Count       uint8
 	InputACLs   []uint32
 	OutputACLs  []uint32
 }
+
+// UnnumberedInterface binds an IP-unnumbered interface to the interface
+// whose addresses it borrows
+type UnnumberedInterface struct {
+	InterfaceID   uint32
+	IPInterfaceID uint32
+}
+
+// InterfaceMetadata is controller-side information stored alongside
+// a VPP interface, it is not pushed to VPP
+type InterfaceMetadata struct {
+	Description string
+	Owner       string
+	// Labels are free-form key/value pairs used to select interfaces
+	Labels map[string]string
+}
+
+// MatchLabels reports whether all selector labels are present in metadata
+// An empty selector value only requires the key to be present
+func (m InterfaceMetadata) MatchLabels(selector map[string]string) bool {
+	for key, value := range selector {
+		label, ok := m.Labels[key]
+		if !ok {
+			return false
+		}
+		if value != "" && label != value {
+			return false
+		}
+	}
+	return true
+}
+
+// URPFMode is the unicast reverse-path forwarding check of an interface
+type URPFMode uint8
+
+const (
+	URPFOff URPFMode = iota
+	// URPFLoose requires a route back to the source via any interface
+	URPFLoose
+	// URPFStrict requires the route back to the source to use the receiving interface
+	URPFStrict
+)
+
+var urpfModeNames = map[URPFMode]string{
+	URPFOff:    "off",
+	URPFLoose:  "loose",
+	URPFStrict: "strict",
+}
+
+func (m URPFMode) String() string {
+	if name, ok := urpfModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("URPFMode(%d)", uint8(m))
+}
+
+func ParseURPFMode(s string) (URPFMode, error) {
+	for mode, name := range urpfModeNames {
+		if name == s {
+			return mode, nil
+		}
+	}
+	return URPFOff, fmt.Errorf("unknown urpf mode: %q", s)
+}
+
+// URPF is the uRPF setting of one address family and direction of an interface
+type URPF struct {
+	InterfaceID uint32
+	IPv6        bool
+	// Egress checks packets sent on the interface instead of received ones
+	Egress bool
+	Mode   URPFMode
+	// TableID is the table the source is looked up in, 0xFFFFFFFF uses the interface's table
+	TableID uint32
+}
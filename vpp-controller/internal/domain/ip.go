@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"fmt"
 	"net"
+	"net/netip"
 )
 
 // Route represents a routing table entry
@@ -19,30 +21,247 @@ type Route struct {
 
 // NextHop defines a forwarding path for a route
 type NextHop struct {
+	// Type selects how the path forwards, NextHopNormal by default
+	Type NextHopType
 	// Destination IP address of the next hop
-	// Required for regular routes, ignored for drop routes
+	// Required for regular routes unless an output interface is given (attached path),
+	// ignored for special path types
 	IP net.IP
 	// Output interface index for packet forwarding
-	// Set to 0xFFFFFFFF for attached/connected routes
+	// Set to 0xFFFFFFFF for a recursive next hop without an interface
 	IfIndex uint32
 	// Weight for load balancing across multiple next hops
 	// Used in ECMP (Equal-Cost Multi-Path) scenarios
 	// Higher values indicate preferred paths
 	Weight uint8
+	// Preference of the path, lower is better, only the best preference paths are used
+	Preference uint8
 	// When true, packets matching this route are dropped
-	// In this case, IP and IfIndex fields are typically ignored
+	// Kept in sync with Type == NextHopDrop
 	Drop bool
+	// NextVRF is the table used by NextHopLookup and NextHopSourceLookup paths
+	NextVRF uint32
+	// UDPEncapID is the UDP encapsulation object used by NextHopUDPEncap paths
+	UDPEncapID uint32
+	// ResolveViaHost restricts recursive resolution to host (/32, /128) routes
+	ResolveViaHost bool
+	// ResolveViaAttached restricts recursive resolution to attached routes
+	ResolveViaAttached bool
+	// Labels is the MPLS out-label stack pushed on the path, outermost first
+	Labels []MPLSLabel
+}
+
+// SamePath reports whether both next hops describe the same forwarding path,
+// weight and preference are not compared
+func (nh NextHop) SamePath(other NextHop) bool {
+	return nh.effectiveType() == other.effectiveType() &&
+		nh.IP.Equal(other.IP) &&
+		nh.IfIndex == other.IfIndex &&
+		nh.NextVRF == other.NextVRF &&
+		nh.UDPEncapID == other.UDPEncapID
+}
+
+func (nh NextHop) effectiveType() NextHopType {
+	if nh.Drop {
+		return NextHopDrop
+	}
+	return nh.Type
+}
+
+// NextHopType is the kind of forwarding performed by a path
+type NextHopType uint8
+
+const (
+	// NextHopNormal forwards via IP and/or output interface
+	// A path with an interface and no IP is an attached (interface-only) path
+	NextHopNormal NextHopType = iota
+	// NextHopDrop silently discards packets
+	NextHopDrop
+	// NextHopLocal delivers packets to the local host
+	NextHopLocal
+	// NextHopUnreachable discards packets and sends ICMP unreachable
+	NextHopUnreachable
+	// NextHopProhibit discards packets and sends ICMP administratively prohibited
+	NextHopProhibit
+	// NextHopLookup performs a destination lookup in NextVRF
+	NextHopLookup
+	// NextHopSourceLookup performs a source address lookup in NextVRF
+	NextHopSourceLookup
+	// NextHopInterfaceRx makes packets appear as received on IfIndex
+	NextHopInterfaceRx
+	// NextHopDVR forwards at L2 out of IfIndex (distributed virtual routing)
+	NextHopDVR
+	// NextHopUDPEncap encapsulates packets using UDPEncapID
+	NextHopUDPEncap
+)
+
+var nextHopTypeNames = map[NextHopType]string{
+	NextHopNormal:       "normal",
+	NextHopDrop:         "drop",
+	NextHopLocal:        "local",
+	NextHopUnreachable:  "unreachable",
+	NextHopProhibit:     "prohibit",
+	NextHopLookup:       "lookup",
+	NextHopSourceLookup: "source-lookup",
+	NextHopInterfaceRx:  "interface-rx",
+	NextHopDVR:          "dvr",
+	NextHopUDPEncap:     "udp-encap",
+}
+
+func (t NextHopType) String() string {
+	if name, ok := nextHopTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(t))
+}
+
+func (t NextHopType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *NextHopType) UnmarshalText(text []byte) error {
+	parsed, err := ParseNextHopType(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// ParseNextHopType converts a path type name, an empty name means NextHopNormal
+func ParseNextHopType(name string) (NextHopType, error) {
+	if name == "" {
+		return NextHopNormal, nil
+	}
+	for t, n := range nextHopTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown next-hop type: %q", name)
+}
+
+// RouteFilter selects routes on listing
+type RouteFilter struct {
+	IPv4 bool
+	IPv6 bool
+	// Within keeps routes whose prefix is contained in the given one, nil means all
+	Within *netip.Prefix
+	// InterfaceID keeps routes with a path via the interface, nil means all
+	InterfaceID *uint32
+	// PathType keeps routes with a path of the type, nil means all
+	PathType *NextHopType
+	// IncludeSystem keeps local, drop and link-local routes installed by VPP
+	IncludeSystem bool
+}
+
+// Match reports whether the route passes the prefix and path filters,
+// address family and system routes are filtered on dump
+func (f RouteFilter) Match(route Route) bool {
+	if f.Within != nil {
+		prefix, err := route.Dst.NetipPrefix()
+		if err != nil || prefix.Bits() < f.Within.Bits() || !f.Within.Contains(prefix.Addr()) {
+			return false
+		}
+	}
+	if f.InterfaceID == nil && f.PathType == nil {
+		return true
+	}
+	for _, nh := range route.NextHops {
+		if f.InterfaceID != nil && nh.IfIndex != *f.InterfaceID {
+			continue
+		}
+		if f.PathType != nil && nh.effectiveType() != *f.PathType {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// RouteLookup is the result of a longest-prefix-match lookup
+type RouteLookup struct {
+	Address net.IP
+	VRF     uint32
+	// Route is the most specific route covering Address
+	Route Route
+	// Chain lists the routes used to resolve recursive next hops
+	// and lookups in other tables, in resolution order
+	Chain []RouteResolution
+}
+
+// RouteResolution is a single step of a recursive resolution chain
+type RouteResolution struct {
+	// Address looked up in VRF at this step
+	Address net.IP
+	VRF     uint32
+	Route   Route
 }
 
 // VRF Virtual Routing and Forwarding
 type VRF struct {
-	ID         uint32
-	Name       string
-	IPv4       bool
-	IPv6       bool
-	RouteCount int
+	ID           uint32
+	Name         string
+	IPv4         bool
+	IPv6         bool
+	RouteCount   int
+	FlowHashIPv4 *FlowHash
+	FlowHashIPv6 *FlowHash
 }
 
+// VRFEntry is a cached VRF, the flow hashes are nil until set through the
+// controller as VPP cannot read them back
 type VRFEntry struct {
-	Name string
+	Name         string
+	FlowHashIPv4 *FlowHash
+	FlowHashIPv6 *FlowHash
+}
+
+// FlowHash selects the packet fields hashed to choose among ECMP paths
+type FlowHash struct {
+	SrcIP   bool
+	DstIP   bool
+	SrcPort bool
+	DstPort bool
+	Proto   bool
+	// Reverse swaps source and destination before hashing
+	Reverse bool
+	// Symmetric hashes both directions of a flow to the same path
+	Symmetric bool
+	// FlowLabel includes the IPv6 flow label
+	FlowLabel bool
+}
+
+// VRFLeak is a set of routes leaking prefixes between a tenant VRF and
+// a shared-services VRF, installed and removed as a unit
+type VRFLeak struct {
+	ID        uint32
+	TenantVRF uint32
+	SharedVRF uint32
+	// Import prefixes of the shared VRF reachable from the tenant VRF,
+	// installed in the tenant VRF as lookups in the shared VRF
+	Import []IPWithPrefix
+	// Export prefixes of the tenant VRF reachable from the shared VRF,
+	// installed in the shared VRF as lookups in the tenant VRF
+	Export []IPWithPrefix
+}
+
+// Routes returns the routes installed for the leak
+func (l VRFLeak) Routes() []Route {
+	routes := make([]Route, 0, len(l.Import)+len(l.Export))
+	for _, prefix := range l.Import {
+		routes = append(routes, Route{
+			Dst:      prefix,
+			VRF:      l.TenantVRF,
+			NextHops: []NextHop{{Type: NextHopLookup, NextVRF: l.SharedVRF, Weight: 1}},
+		})
+	}
+	for _, prefix := range l.Export {
+		routes = append(routes, Route{
+			Dst:      prefix,
+			VRF:      l.SharedVRF,
+			NextHops: []NextHop{{Type: NextHopLookup, NextVRF: l.TenantVRF, Weight: 1}},
+		})
+	}
+	return routes
 }
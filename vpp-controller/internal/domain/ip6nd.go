@@ -0,0 +1,63 @@
+package domain
+
+// RAConfig is the desired IPv6 router advertisement state of an interface
+type RAConfig struct {
+	// Suppress stops sending router advertisements
+	Suppress bool
+	// Managed sets the M flag, addresses are available via DHCPv6
+	Managed bool
+	// Other sets the O flag, other configuration is available via DHCPv6
+	Other bool
+	// LinkLayerAddress includes the source link-layer address option
+	LinkLayerAddress bool
+	// SendUnicast answers router solicitations with unicast advertisements
+	SendUnicast bool
+	// Cease sends a final advertisement with zero router lifetime
+	Cease bool
+	// RouterLifetime in seconds, 0 means not a default router, nil keeps the current value
+	RouterLifetime *uint32
+	// MaxInterval and MinInterval in seconds between unsolicited advertisements,
+	// zero keeps the current value
+	MaxInterval uint32
+	MinInterval uint32
+	// InitialCount and InitialInterval control the initial burst of advertisements,
+	// zero keeps the current value
+	InitialCount    uint32
+	InitialInterval uint32
+}
+
+// RAPrefix is a prefix information option carried in router advertisements
+type RAPrefix struct {
+	Prefix IPWithPrefix
+	// UseDefault applies the default valid and preferred lifetimes
+	UseDefault   bool
+	NoAdvertise  bool
+	OffLink      bool
+	NoAutoconfig bool
+	NoOnlink     bool
+	// ValidLifetime and PreferredLifetime in seconds
+	ValidLifetime     uint32
+	PreferredLifetime uint32
+}
+
+// RAInfo is the effective router advertisement state reported by VPP
+type RAInfo struct {
+	InterfaceID           uint32
+	SendRA                bool
+	Managed               bool
+	Other                 bool
+	LinkLayerAddress      bool
+	SendUnicast           bool
+	Cease                 bool
+	CurHopLimit           uint8
+	RouterLifetime        uint16
+	ReachableTime         uint32
+	RetransmitInterval    uint32
+	LinkMTU               uint32
+	MaxInterval           float64
+	MinInterval           float64
+	AdvertisementsSent    uint32
+	SolicitationsReceived uint32
+	SolicitationsDropped  uint32
+	Prefixes              []RAPrefix
+}
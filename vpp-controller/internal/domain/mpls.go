@@ -0,0 +1,84 @@
+package domain
+
+import "fmt"
+
+// MaxLabelStack is the deepest out-label stack a path can push
+const MaxLabelStack = 16
+
+// MPLSLabel is an out label pushed by a path
+type MPLSLabel struct {
+	Label uint32
+	// TTL of the pushed label, 0 lets VPP pick the default
+	TTL uint8
+	Exp uint8
+	// Uniform copies TTL and DSCP from the inner header instead of the pipe model
+	Uniform bool
+}
+
+// MPLSTable is a label FIB, table 0 must exist before MPLS is enabled on interfaces
+type MPLSTable struct {
+	ID   uint32
+	Name string
+}
+
+// MPLSAction is what a local-label route does with matching packets
+type MPLSAction uint8
+
+const (
+	// MPLSSwap replaces the label with the out labels of each next hop
+	MPLSSwap MPLSAction = iota
+	// MPLSPop removes the label and forwards the payload to the next hops
+	MPLSPop
+	// MPLSDisposition removes the bottom label and looks the payload up in a VRF
+	MPLSDisposition
+)
+
+var mplsActionNames = map[MPLSAction]string{
+	MPLSSwap:        "swap",
+	MPLSPop:         "pop",
+	MPLSDisposition: "disposition",
+}
+
+func (a MPLSAction) String() string {
+	if name, ok := mplsActionNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("MPLSAction(%d)", uint8(a))
+}
+
+func (a MPLSAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *MPLSAction) UnmarshalText(text []byte) error {
+	action, err := ParseMPLSAction(string(text))
+	if err != nil {
+		return err
+	}
+	*a = action
+	return nil
+}
+
+func ParseMPLSAction(s string) (MPLSAction, error) {
+	for action, name := range mplsActionNames {
+		if name == s {
+			return action, nil
+		}
+	}
+	return MPLSSwap, fmt.Errorf("unknown mpls action: %q", s)
+}
+
+// MPLSRoute is a local-label entry of an MPLS table
+type MPLSRoute struct {
+	Table uint32
+	Label uint32
+	// EOS matches the label at the bottom of the stack
+	EOS bool
+	// PayloadIPv6 is the protocol below the bottom label, used by EOS routes
+	PayloadIPv6 bool
+	Action      MPLSAction
+	// VRF is the table disposition routes look the payload up in
+	VRF uint32
+	// NextHops of swap and pop routes, swap next hops carry out labels
+	NextHops []NextHop
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"net"
+)
+
+// MRoute is a multicast forwarding entry, a nil Source makes it a (*,G) route
+type MRoute struct {
+	VRF    uint32
+	Source net.IP
+	// Group address with prefix length, a full-length prefix for a single group
+	Group IPWithPrefix
+	// RPFID accepts packets tagged with the ID instead of an accepting interface
+	RPFID uint32
+	// Signal notifies the control plane of packets hitting the entry
+	Signal bool
+	Drop   bool
+	// Connected signals packets from directly connected sources
+	Connected bool
+	// AcceptAllInterfaces accepts packets from every interface, skipping the RPF check
+	AcceptAllInterfaces bool
+	Paths               []MRoutePath
+}
+
+// MRoutePath is an interface of a multicast route with its role
+type MRoutePath struct {
+	InterfaceID uint32
+	// Accept is set on interfaces packets are expected to arrive on (RPF)
+	Accept bool
+	// Forward is set on interfaces packets are replicated to
+	Forward       bool
+	SignalPresent bool
+	NegateSignal  bool
+	DontPreserve  bool
+}
+
+func (r MRoute) IsIPv6() bool {
+	return r.Group.IsIPv6()
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"net"
+)
+
+// Neighbor is an ARP (IPv4) or ND (IPv6) table entry
+type Neighbor struct {
+	InterfaceID uint32
+	IP          net.IP
+	MAC         net.HardwareAddr
+	// Static entries are never aged out
+	Static bool
+	// NoFIBEntry entries resolve adjacencies without installing a /32 or /128 host route
+	NoFIBEntry bool
+	// Age in seconds since the entry was last refreshed, only set on dump
+	Age float64
+}
+
+// NeighborFilter selects neighbors on dump
+type NeighborFilter struct {
+	// InterfaceID limits the dump to one interface, 0xFFFFFFFF means all
+	InterfaceID uint32
+	// VRF limits the dump to interfaces bound to the table, nil means all
+	VRF  *uint32
+	IPv4 bool
+	IPv6 bool
+}
+
+// NeighborConfig controls aging and limits of the neighbor table per address family
+type NeighborConfig struct {
+	IPv6 bool
+	// MaxNumber is the maximum number of dynamic entries
+	MaxNumber uint32
+	// MaxAge in seconds, 0 disables aging
+	MaxAge uint32
+	// Recycle the oldest entry when the table is full
+	Recycle bool
+}
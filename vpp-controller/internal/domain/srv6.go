@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+)
+
+// MaxSegments is the longest segment list of an SR policy
+const MaxSegments = 16
+
+// SRv6Behavior is the function bound to a local SID
+type SRv6Behavior uint8
+
+const (
+	// SRv6End advances to the next segment
+	SRv6End SRv6Behavior = iota
+	// SRv6EndX advances to the next segment and forwards to a layer-3 adjacency
+	SRv6EndX
+	// SRv6EndDT4 decapsulates and looks the inner IPv4 packet up in a VRF
+	SRv6EndDT4
+	// SRv6EndDT6 decapsulates and looks the inner IPv6 packet up in a VRF
+	SRv6EndDT6
+	// SRv6EndDT46 decapsulates and looks the inner IPv4 or IPv6 packet up in a VRF
+	SRv6EndDT46
+)
+
+var srv6BehaviorNames = map[SRv6Behavior]string{
+	SRv6End:     "End",
+	SRv6EndX:    "End.X",
+	SRv6EndDT4:  "End.DT4",
+	SRv6EndDT6:  "End.DT6",
+	SRv6EndDT46: "End.DT46",
+}
+
+func (b SRv6Behavior) String() string {
+	if name, ok := srv6BehaviorNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("SRv6Behavior(%d)", uint8(b))
+}
+
+func ParseSRv6Behavior(s string) (SRv6Behavior, error) {
+	for behavior, name := range srv6BehaviorNames {
+		if name == s {
+			return behavior, nil
+		}
+	}
+	return SRv6End, fmt.Errorf("unknown srv6 behavior: %q", s)
+}
+
+// IsDecap reports whether the behavior decapsulates into a VRF
+func (b SRv6Behavior) IsDecap() bool {
+	return b == SRv6EndDT4 || b == SRv6EndDT6 || b == SRv6EndDT46
+}
+
+// SRv6LocalSID is a segment instantiated on this node
+type SRv6LocalSID struct {
+	SID      net.IP
+	Behavior SRv6Behavior
+	// PSP pops the segment routing header at the penultimate segment
+	PSP bool
+	// Table is the FIB table the SID is installed in
+	Table uint32
+	// VRF is the table End.DT* behaviors look decapsulated packets up in
+	VRF uint32
+	// InterfaceID and NextHop are the adjacency End.X forwards to
+	InterfaceID uint32
+	NextHop     net.IP
+}
+
+// SRv6SegmentList is one weighted path of an SR policy
+type SRv6SegmentList struct {
+	// Index identifies the list within its policy, only set on dump
+	Index    uint32
+	Weight   uint32
+	Segments []net.IP
+}
+
+// SRv6Policy steers traffic over one of its segment lists, it is
+// identified by its binding SID
+type SRv6Policy struct {
+	BSID net.IP
+	// Encap encapsulates in an outer IPv6 header (H.Encaps) instead of inserting an SRH
+	Encap bool
+	// Spray replicates packets to every segment list instead of load balancing
+	Spray bool
+	// Table is the FIB table the binding SID is installed in
+	Table        uint32
+	SegmentLists []SRv6SegmentList
+}
+
+// SRv6Steering redirects a prefix of a VRF into a policy
+type SRv6Steering struct {
+	BSID   net.IP
+	VRF    uint32
+	Prefix IPWithPrefix
+}
@@ -0,0 +1,18 @@
+package vpp
+
+import (
+	"context"
+	"fmt"
+
+	"go.fd.io/govpp/binapi/vlib"
+)
+
+// RunCLI executes a debug CLI command for features without a binary API
+// and returns its output
+func RunCLI(ctx context.Context, client *Client, cmd string) (string, error) {
+	reply, err := DoRequest[*vlib.CliInband, *vlib.CliInbandReply](client, ctx, &vlib.CliInband{Cmd: cmd})
+	if err != nil {
+		return "", fmt.Errorf("cli %q: %w", cmd, err)
+	}
+	return reply.Reply, nil
+}
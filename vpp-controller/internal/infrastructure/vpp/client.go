@@ -2,6 +2,7 @@ package vpp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -68,6 +69,26 @@ func (c *Client) NewStream(ctx context.Context) (api.Stream, error) {
 	return stream, nil
 }
 
+func (c *Client) WatchEvent(ctx context.Context, event api.Message) (api.Watcher, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("vpp client closed")
+	}
+
+	if c.conn == nil {
+		return nil, fmt.Errorf("vpp not connected")
+	}
+
+	watcher, err := c.conn.WatchEvent(ctx, event)
+	if err != nil {
+		return nil, fmt.Errorf("watch event failed: %w", err)
+	}
+
+	return watcher, nil
+}
+
 func (c *Client) Do(ctx context.Context, fn func(stream api.Stream) error) error {
 	stream, err := c.NewStream(ctx)
 	if err != nil {
@@ -106,46 +127,74 @@ func (c *Client) IsConnected() bool {
 }
 
 func (c *Client) SendMultiRequest(ctx context.Context, request api.Message) ([]api.Message, error) {
+	var messages []api.Message
+
+	err := c.StreamMultiRequest(ctx, request, func(message api.Message) error {
+		messages = append(messages, message)
+		return nil
+	})
+	if err != nil {
+		var partial *PartialDumpError
+		if errors.As(err, &partial) {
+			return messages, err
+		}
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// PartialDumpError is returned when some replies of a dump carried
+// a non-zero retval, the remaining replies were delivered
+type PartialDumpError struct {
+	Errors []error
+}
+
+func (e *PartialDumpError) Error() string {
+	return fmt.Sprintf("completed with %d errors: %v", len(e.Errors), e.Errors)
+}
+
+// StreamMultiRequest sends a dump request and passes every reply to fn as it
+// arrives, an error returned by fn stops the dump
+func (c *Client) StreamMultiRequest(ctx context.Context, request api.Message, fn func(api.Message) error) error {
 	stream, err := c.NewStream(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("create stream: %w", err)
+		return fmt.Errorf("create stream: %w", err)
 	}
 	defer stream.Close()
 
 	if err := stream.SendMsg(request); err != nil {
 		logger.Error("send request failed", zap.Error(err))
-		return nil, fmt.Errorf("send request: %w", err)
+		return fmt.Errorf("send request: %w", err)
 	}
 	err = stream.SendMsg(&memclnt.ControlPing{})
 	if err != nil {
 		logger.Error("send ping failed", zap.Error(err))
-		return nil, err
+		return err
 	}
 
-	var (
-		messages []api.Message
-		errors   []error
-	)
-
+	var errs []error
 	for {
 		message, err := stream.RecvMsg()
 		if err != nil {
 			logger.Error("recv message failed", zap.Error(err))
-			return nil, fmt.Errorf("receive message: %w", err)
+			return fmt.Errorf("receive message: %w", err)
 		}
 		switch message.(type) {
 		case *memclnt.ControlPingReply:
-			if len(errors) > 0 {
-				return messages, fmt.Errorf("completed with %d errors: %v", len(errors), errors)
+			if len(errs) > 0 {
+				return &PartialDumpError{Errors: errs}
 			}
-			return messages, nil
+			return nil
 		default:
 			// TODO: Retval check via reflection is suboptimal
 			if retval := getRetval(message); retval != 0 {
-				errors = append(errors, fmt.Errorf("%w:%s", api.RetvalToVPPApiError(retval), message))
+				errs = append(errs, fmt.Errorf("%w:%s", api.RetvalToVPPApiError(retval), message))
 				continue
 			}
-			messages = append(messages, message)
+			if err := fn(message); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -195,6 +244,23 @@ func Dump[T any](
 	return results, nil
 }
 
+// Walk streams the converted replies of a dump to fn without buffering them
+func Walk[T any](
+	ctx context.Context,
+	client *Client,
+	request api.Message,
+	converter func(api.Message) (T, bool),
+	fn func(T) error,
+) error {
+	return client.StreamMultiRequest(ctx, request, func(message api.Message) error {
+		item, ok := converter(message)
+		if !ok {
+			return nil
+		}
+		return fn(item)
+	})
+}
+
 func DumpWithTimeout[T any](
 	client *Client,
 	timeout time.Duration,
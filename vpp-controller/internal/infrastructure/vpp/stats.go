@@ -0,0 +1,73 @@
+package vpp
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"go.fd.io/govpp/adapter"
+	"go.fd.io/govpp/adapter/statsclient"
+)
+
+// StatsClient reads counters from the VPP stats segment
+type StatsClient struct {
+	client *statsclient.StatsClient
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewStatsClient(socketPath string) (*StatsClient, error) {
+	client := statsclient.NewStatsClient(socketPath)
+	if err := client.Connect(); err != nil {
+		return nil, fmt.Errorf("stats connect failed: %w", err)
+	}
+	return &StatsClient{
+		client: client,
+	}, nil
+}
+
+// CombinedCounters returns the combined counter vectors whose names match the
+// regular expression, keyed by name
+func (c *StatsClient) CombinedCounters(pattern string) (map[string]adapter.CombinedCounterStat, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("vpp stats client closed")
+	}
+
+	entries, err := c.client.DumpStats(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("dump stats: %w", err)
+	}
+	counters := make(map[string]adapter.CombinedCounterStat, len(entries))
+	for _, entry := range entries {
+		if stat, ok := entry.Data.(adapter.CombinedCounterStat); ok {
+			counters[string(entry.Name)] = stat
+		}
+	}
+	return counters, nil
+}
+
+// CombinedCounter returns the combined counter vector with the exact name,
+// false when the stats segment has no such entry
+func (c *StatsClient) CombinedCounter(name string) (adapter.CombinedCounterStat, bool, error) {
+	counters, err := c.CombinedCounters("^" + regexp.QuoteMeta(name) + "$")
+	if err != nil {
+		return nil, false, err
+	}
+	stat, ok := counters[name]
+	return stat, ok, nil
+}
+
+func (c *StatsClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	c.client.Disconnect()
+}
@@ -0,0 +1,71 @@
+package mapper
+
+import (
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/abf"
+	"go.fd.io/govpp/binapi/interface_types"
+)
+
+// BuildAbfPolicy converts the policy, paths without a next-hop address take
+// the address family of the other paths
+func BuildAbfPolicy(policy domain.ABFPolicy) (abf.AbfPolicy, error) {
+	isIPv6 := false
+	for _, nh := range policy.NextHops {
+		if nh.IP != nil {
+			isIPv6 = nh.IP.To4() == nil
+			break
+		}
+	}
+
+	paths, err := BuildFibPaths(policy.NextHops, isIPv6)
+	if err != nil {
+		return abf.AbfPolicy{}, err
+	}
+	if len(paths) > 255 {
+		return abf.AbfPolicy{}, fmt.Errorf("abf policy has %d paths, at most 255 are supported", len(paths))
+	}
+
+	return abf.AbfPolicy{
+		PolicyID: policy.ID,
+		ACLIndex: uint32(policy.ACLID),
+		NPaths:   uint8(len(paths)),
+		Paths:    paths,
+	}, nil
+}
+
+func ConvertAbfPolicy(policy abf.AbfPolicy) (domain.ABFPolicy, error) {
+	nextHops := make([]domain.NextHop, 0, len(policy.Paths))
+	for _, path := range policy.Paths {
+		nh, err := ConvertFibPathToDomainNextHop(path)
+		if err != nil {
+			return domain.ABFPolicy{}, fmt.Errorf("abf policy %d: %w", policy.PolicyID, err)
+		}
+		nextHops = append(nextHops, nh)
+	}
+
+	return domain.ABFPolicy{
+		ID:       policy.PolicyID,
+		ACLID:    domain.AclID(policy.ACLIndex),
+		NextHops: nextHops,
+	}, nil
+}
+
+func BuildAbfItfAttach(attachment domain.ABFAttachment) abf.AbfItfAttach {
+	return abf.AbfItfAttach{
+		PolicyID:  attachment.PolicyID,
+		SwIfIndex: interface_types.InterfaceIndex(attachment.InterfaceID),
+		Priority:  attachment.Priority,
+		IsIPv6:    attachment.IPv6,
+	}
+}
+
+func ConvertAbfItfAttach(attach abf.AbfItfAttach) domain.ABFAttachment {
+	return domain.ABFAttachment{
+		PolicyID:    attach.PolicyID,
+		InterfaceID: uint32(attach.SwIfIndex),
+		Priority:    attach.Priority,
+		IPv6:        attach.IsIPv6,
+	}
+}
@@ -3,6 +3,8 @@ package mapper
 import (
 	"fmt"
 	"net"
+	"regexp"
+	"strconv"
 
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 	"go.fd.io/govpp/binapi/acl_types"
@@ -20,8 +22,13 @@ func ConvertACLRule(aclRule domain.ACLRule) (acl_types.ACLRule, error) {
 		return acl_types.ACLRule{}, fmt.Errorf("invalid dst prefix: %w", err)
 	}
 
+	action, err := BuildACLAction(aclRule.Action)
+	if err != nil {
+		return acl_types.ACLRule{}, err
+	}
+
 	return acl_types.ACLRule{
-		IsPermit: acl_types.ACLAction(aclRule.Action),
+		IsPermit: action,
 
 		SrcPrefix: srcPrefix,
 		DstPrefix: dstPrefix,
@@ -39,6 +46,32 @@ func ConvertACLRule(aclRule domain.ACLRule) (acl_types.ACLRule, error) {
 	}, nil
 }
 
+func BuildACLAction(action domain.ACLAction) (acl_types.ACLAction, error) {
+	switch action {
+	case domain.ACLDeny:
+		return acl_types.ACL_ACTION_API_DENY, nil
+	case domain.ACLPermit:
+		return acl_types.ACL_ACTION_API_PERMIT, nil
+	case domain.ACLPermitReflect:
+		return acl_types.ACL_ACTION_API_PERMIT_REFLECT, nil
+	default:
+		return 0, fmt.Errorf("unsupported acl action: %v", action)
+	}
+}
+
+func ConvertACLAction(action acl_types.ACLAction) (domain.ACLAction, error) {
+	switch action {
+	case acl_types.ACL_ACTION_API_DENY:
+		return domain.ACLDeny, nil
+	case acl_types.ACL_ACTION_API_PERMIT:
+		return domain.ACLPermit, nil
+	case acl_types.ACL_ACTION_API_PERMIT_REFLECT:
+		return domain.ACLPermitReflect, nil
+	default:
+		return 0, fmt.Errorf("unsupported acl action: %d", action)
+	}
+}
+
 func IPWithPrefixToTypes(prefix domain.IPWithPrefix) (ip_types.Prefix, error) {
 	if prefix.Address == "" {
 		return ip_types.Prefix{}, fmt.Errorf("empty ip address")
@@ -100,8 +133,13 @@ func ConvertVPPACLRule(rule acl_types.ACLRule) (domain.ACLRule, error) {
 		return domain.ACLRule{}, fmt.Errorf("invalid dst prefix: %w", err)
 	}
 
+	action, err := ConvertACLAction(rule.IsPermit)
+	if err != nil {
+		return domain.ACLRule{}, err
+	}
+
 	return domain.ACLRule{
-		Action:        domain.ACLAction(rule.IsPermit),
+		Action:        action,
 		Proto:         uint8(rule.Proto),
 		Src:           src,
 		Dst:           dst,
@@ -125,3 +163,33 @@ func ConvertVPPACLRules(rules []acl_types.ACLRule) ([]domain.ACLRule, error) {
 	}
 	return domainRules, nil
 }
+
+// aclSessionStatsLine matches the per-thread "connection add/del stats" lines of
+// "show acl-plugin sessions"
+var aclSessionStatsLine = regexp.MustCompile(`sw_if_index (\d+): add (\d+) - del (\d+)`)
+
+// ParseACLSessionStats sums the per-thread session counters of every interface
+func ParseACLSessionStats(output string) map[uint32]domain.ACLSessionStats {
+	stats := make(map[uint32]domain.ACLSessionStats)
+	for _, match := range aclSessionStatsLine.FindAllStringSubmatch(output, -1) {
+		ifIndex, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			continue
+		}
+		added, _ := strconv.ParseUint(match[2], 10, 64)
+		deleted, _ := strconv.ParseUint(match[3], 10, 64)
+
+		entry := stats[uint32(ifIndex)]
+		entry.InterfaceID = uint32(ifIndex)
+		entry.Added += added
+		entry.Deleted += deleted
+		stats[uint32(ifIndex)] = entry
+	}
+	for ifIndex, entry := range stats {
+		if entry.Added > entry.Deleted {
+			entry.Active = entry.Added - entry.Deleted
+		}
+		stats[ifIndex] = entry
+	}
+	return stats
+}
@@ -0,0 +1,73 @@
+package mapper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+)
+
+// showACLSessions follows the layout printed by acl_plugin_show_sessions of
+// VPP for two threads
+const showACLSessions = `Sessions total: add 17 - del 5 = 12
+Sessions active: add 17 - deact 5 = 12
+Sessions being purged: deact 5 - del 5 = 0
+now: 4306386913392706 clocks per second: 2600000000
+
+
+Per-thread data:
+Thread #0:
+  connection add/del stats:
+    sw_if_index 0: add 0 - del 0 = 0; epoch chg: 0
+    sw_if_index 1: add 4 - del 1 = 3; epoch chg: 0
+  connection timeout type lists:
+  fa_conn_list_head[0]: -1
+  fa_conn_list_head[1]: 3
+  fa_conn_list_head[2]: -1
+  Next expiry time: 0
+  Requeue until time: 0
+  Current time wait interval: 0
+  Count of deleted sessions: 1
+  Delete already deleted: 0
+  Session timers restarted: 0
+  Swipe until this time: 0
+  sw_if_index serviced bitmap:
+  pending clear intfc bitmap :
+  clear in progress: 0
+  interrupt is pending: 0
+  interrupt is needed: 0
+  interrupt is unwanted: 1
+  interrupt generation: 3
+  received session change requests: 0
+  sent session change requests: 0
+Thread #1:
+  connection add/del stats:
+    sw_if_index 0: add 0 - del 0 = 0; epoch chg: 0
+    sw_if_index 1: add 10 - del 4 = 6; epoch chg: 0
+    sw_if_index 2: add 3 - del 0 = 3; epoch chg: 1
+  connection timeout type lists:
+  fa_conn_list_head[0]: 7
+  fa_conn_list_head[1]: 9
+  fa_conn_list_head[2]: -1
+
+
+Conn cleaner thread counters:
+             0 - delete_by_sw_index events
+             0 - delete_by_sw_index handled ok
+Interrupt generation: 3
+Sessions per interval: min 1 max 100 increment: 0.500000 ms current: 10.000000 ms
+`
+
+func TestParseACLSessionStats(t *testing.T) {
+	want := map[uint32]domain.ACLSessionStats{
+		0: {InterfaceID: 0},
+		1: {InterfaceID: 1, Added: 14, Deleted: 5, Active: 9},
+		2: {InterfaceID: 2, Added: 3, Active: 3},
+	}
+	if got := ParseACLSessionStats(showACLSessions); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseACLSessionStats() = %+v, want %+v", got, want)
+	}
+	if got := ParseACLSessionStats(""); len(got) != 0 {
+		t.Errorf("ParseACLSessionStats(\"\") = %+v, want empty", got)
+	}
+}
@@ -0,0 +1,29 @@
+package mapper
+
+import (
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/ip"
+)
+
+func BuildFlowHashConfig(flowHash domain.FlowHash) ip.IPFlowHashConfig {
+	var config ip.IPFlowHashConfig
+	flags := []struct {
+		set  bool
+		flag ip.IPFlowHashConfig
+	}{
+		{flowHash.SrcIP, ip.IP_API_FLOW_HASH_SRC_IP},
+		{flowHash.DstIP, ip.IP_API_FLOW_HASH_DST_IP},
+		{flowHash.SrcPort, ip.IP_API_FLOW_HASH_SRC_PORT},
+		{flowHash.DstPort, ip.IP_API_FLOW_HASH_DST_PORT},
+		{flowHash.Proto, ip.IP_API_FLOW_HASH_PROTO},
+		{flowHash.Reverse, ip.IP_API_FLOW_HASH_REVERSE},
+		{flowHash.Symmetric, ip.IP_API_FLOW_HASH_SYMETRIC},
+		{flowHash.FlowLabel, ip.IP_API_FLOW_HASH_FLOW_LABEL},
+	}
+	for _, f := range flags {
+		if f.set {
+			config |= f.flag
+		}
+	}
+	return config
+}
@@ -0,0 +1,45 @@
+package mapper
+
+import (
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/ip6_nd"
+)
+
+func ConvertRADetails(details *ip6_nd.SwInterfaceIP6ndRaDetails) (domain.RAInfo, error) {
+	prefixes := make([]domain.RAPrefix, 0, len(details.Prefixes))
+	for _, p := range details.Prefixes {
+		prefix, err := IPWithPrefixFromTypes(p.Prefix)
+		if err != nil {
+			return domain.RAInfo{}, err
+		}
+		prefixes = append(prefixes, domain.RAPrefix{
+			Prefix:            prefix,
+			NoAdvertise:       p.NoAdvertise,
+			NoAutoconfig:      !p.AutonomousFlag,
+			NoOnlink:          !p.OnlinkFlag,
+			ValidLifetime:     p.ValLifetime,
+			PreferredLifetime: p.PrefLifetime,
+		})
+	}
+
+	return domain.RAInfo{
+		InterfaceID:           uint32(details.SwIfIndex),
+		SendRA:                details.SendRadv,
+		Managed:               details.AdvManagedFlag,
+		Other:                 details.AdvOtherFlag,
+		LinkLayerAddress:      details.AdvLinkLayerAddress,
+		SendUnicast:           details.SendUnicast,
+		Cease:                 details.CeaseRadv,
+		CurHopLimit:           details.CurHopLimit,
+		RouterLifetime:        details.AdvRouterLifetime,
+		ReachableTime:         details.AdvNeighborReachableTime,
+		RetransmitInterval:    details.AdvRetransmitInterval,
+		LinkMTU:               details.AdvLinkMtu,
+		MaxInterval:           details.MaxRadvInterval,
+		MinInterval:           details.MinRadvInterval,
+		AdvertisementsSent:    details.NAdvertisementsSent,
+		SolicitationsReceived: details.NSolicitationsRcvd,
+		SolicitationsDropped:  details.NSolicitationsDropped,
+		Prefixes:              prefixes,
+	}, nil
+}
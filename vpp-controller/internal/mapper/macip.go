@@ -0,0 +1,58 @@
+package mapper
+
+import (
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/acl_types"
+	"go.fd.io/govpp/binapi/ethernet_types"
+)
+
+func ConvertMACIPRules(rules []domain.MACIPRule) ([]acl_types.MacipACLRule, error) {
+	vppRules := make([]acl_types.MacipACLRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Action != domain.ACLDeny && rule.Action != domain.ACLPermit {
+			return nil, fmt.Errorf("rule %d: macip rules only permit or deny, got %v", i, rule.Action)
+		}
+		srcPrefix, err := IPWithPrefixToTypes(rule.Src)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: invalid src prefix: %w", i, err)
+		}
+		if len(rule.MAC) != 6 || len(rule.MACMask) != 6 {
+			return nil, fmt.Errorf("rule %d: mac and mac mask must be 6 bytes", i)
+		}
+		action, err := BuildACLAction(rule.Action)
+		if err != nil {
+			return nil, err
+		}
+
+		vppRules = append(vppRules, acl_types.MacipACLRule{
+			IsPermit:   action,
+			SrcMac:     ethernet_types.NewMacAddress(rule.MAC),
+			SrcMacMask: ethernet_types.NewMacAddress(rule.MACMask),
+			SrcPrefix:  srcPrefix,
+		})
+	}
+	return vppRules, nil
+}
+
+func ConvertVPPMACIPRules(rules []acl_types.MacipACLRule) ([]domain.MACIPRule, error) {
+	domainRules := make([]domain.MACIPRule, 0, len(rules))
+	for _, r := range rules {
+		action, err := ConvertACLAction(r.IsPermit)
+		if err != nil {
+			return nil, err
+		}
+		src, err := IPWithPrefixFromTypes(r.SrcPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid src prefix: %w", err)
+		}
+		domainRules = append(domainRules, domain.MACIPRule{
+			Action:  action,
+			MAC:     r.SrcMac.ToMAC(),
+			MACMask: r.SrcMacMask.ToMAC(),
+			Src:     src,
+		})
+	}
+	return domainRules, nil
+}
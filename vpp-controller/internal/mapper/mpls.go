@@ -0,0 +1,104 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/fib_types"
+	"go.fd.io/govpp/binapi/mpls"
+)
+
+// maxMPLSLabel is the largest 20-bit label value
+const maxMPLSLabel = 1<<20 - 1
+
+func BuildMplsRoute(route domain.MPLSRoute) (mpls.MplsRoute, error) {
+	if route.Label > maxMPLSLabel {
+		return mpls.MplsRoute{}, fmt.Errorf("label %d exceeds %d", route.Label, maxMPLSLabel)
+	}
+
+	payloadProto := fib_types.FIB_API_PATH_NH_PROTO_IP4
+	if route.PayloadIPv6 {
+		payloadProto = fib_types.FIB_API_PATH_NH_PROTO_IP6
+	}
+
+	var paths []fib_types.FibPath
+	switch route.Action {
+	case domain.MPLSSwap, domain.MPLSPop:
+		if len(route.NextHops) == 0 {
+			return mpls.MplsRoute{}, fmt.Errorf("%v route requires next hops", route.Action)
+		}
+		for _, nh := range route.NextHops {
+			if route.Action == domain.MPLSSwap && len(nh.Labels) == 0 {
+				return mpls.MplsRoute{}, errors.New("swap next hops require out labels")
+			}
+			if route.Action == domain.MPLSPop && len(nh.Labels) > 0 {
+				return mpls.MplsRoute{}, errors.New("pop next hops must not carry out labels")
+			}
+		}
+		var err error
+		paths, err = BuildFibPaths(route.NextHops, route.PayloadIPv6)
+		if err != nil {
+			return mpls.MplsRoute{}, err
+		}
+	case domain.MPLSDisposition:
+		if !route.EOS {
+			return mpls.MplsRoute{}, errors.New("disposition requires an end-of-stack route")
+		}
+		paths = []fib_types.FibPath{{
+			SwIfIndex: invalidIndex,
+			TableID:   route.VRF,
+			Type:      fib_types.FIB_API_PATH_TYPE_NORMAL,
+			Proto:     payloadProto,
+		}}
+	default:
+		return mpls.MplsRoute{}, fmt.Errorf("unsupported mpls action: %v", route.Action)
+	}
+
+	var eos uint8
+	if route.EOS {
+		eos = 1
+	}
+	return mpls.MplsRoute{
+		MrTableID:  route.Table,
+		MrLabel:    route.Label,
+		MrEos:      eos,
+		MrEosProto: uint8(payloadProto),
+		MrNPaths:   uint8(len(paths)),
+		MrPaths:    paths,
+	}, nil
+}
+
+func ConvertMplsRouteDetails(details *mpls.MplsRouteDetails) (domain.MPLSRoute, error) {
+	r := details.MrRoute
+	route := domain.MPLSRoute{
+		Table:       r.MrTableID,
+		Label:       r.MrLabel,
+		EOS:         r.MrEos != 0,
+		PayloadIPv6: fib_types.FibPathNhProto(r.MrEosProto) == fib_types.FIB_API_PATH_NH_PROTO_IP6,
+		Action:      domain.MPLSPop,
+	}
+
+	nextHops := make([]domain.NextHop, 0, len(r.MrPaths))
+	for _, path := range r.MrPaths {
+		nh, err := ConvertFibPathToDomainNextHop(path)
+		if err != nil {
+			return domain.MPLSRoute{}, err
+		}
+		nextHops = append(nextHops, nh)
+	}
+
+	if len(nextHops) == 1 && nextHops[0].Type == domain.NextHopLookup && len(nextHops[0].Labels) == 0 {
+		route.Action = domain.MPLSDisposition
+		route.VRF = nextHops[0].NextVRF
+		return route, nil
+	}
+	for _, nh := range nextHops {
+		if len(nh.Labels) > 0 {
+			route.Action = domain.MPLSSwap
+			break
+		}
+	}
+	route.NextHops = nextHops
+	return route, nil
+}
@@ -0,0 +1,158 @@
+package mapper
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/fib_types"
+	"go.fd.io/govpp/binapi/ip"
+	"go.fd.io/govpp/binapi/ip_types"
+	"go.fd.io/govpp/binapi/mfib_types"
+)
+
+func BuildMroute(route domain.MRoute) (ip.IPMroute, error) {
+	group := route.Group.ToNetIP()
+	if group == nil || !group.IsMulticast() {
+		return ip.IPMroute{}, fmt.Errorf("invalid multicast group: %s", route.Group.Address)
+	}
+	isIPv6 := route.IsIPv6()
+
+	prefix := ip_types.Mprefix{
+		Af:               AddressFamily(isIPv6),
+		GrpAddressLength: uint16(route.Group.Prefix),
+		GrpAddress:       ip_types.NewAddress(group).Un,
+	}
+	if route.Source != nil {
+		if (route.Source.To4() == nil) != isIPv6 {
+			return ip.IPMroute{}, fmt.Errorf("source %s and group %s address families differ",
+				route.Source, route.Group.Address)
+		}
+		if route.Group.Prefix != hostPrefixLength(isIPv6) {
+			return ip.IPMroute{}, fmt.Errorf("source %s requires a single group, got %s/%d",
+				route.Source, route.Group.Address, route.Group.Prefix)
+		}
+		prefix.SrcAddress = ip_types.NewAddress(route.Source).Un
+		// the mfib only matches the source when the length spans source and group
+		prefix.GrpAddressLength = 2 * uint16(hostPrefixLength(isIPv6))
+	}
+
+	proto := fib_types.FIB_API_PATH_NH_PROTO_IP4
+	if isIPv6 {
+		proto = fib_types.FIB_API_PATH_NH_PROTO_IP6
+	}
+	paths := make([]mfib_types.MfibPath, 0, len(route.Paths))
+	for _, p := range route.Paths {
+		paths = append(paths, mfib_types.MfibPath{
+			ItfFlags: buildMfibItfFlags(p),
+			Path: fib_types.FibPath{
+				SwIfIndex: p.InterfaceID,
+				Type:      fib_types.FIB_API_PATH_TYPE_NORMAL,
+				Proto:     proto,
+			},
+		})
+	}
+
+	return ip.IPMroute{
+		TableID:    route.VRF,
+		EntryFlags: buildMfibEntryFlags(route),
+		RpfID:      route.RPFID,
+		Prefix:     prefix,
+		NPaths:     uint8(len(paths)),
+		Paths:      paths,
+	}, nil
+}
+
+func buildMfibEntryFlags(route domain.MRoute) mfib_types.MfibEntryFlags {
+	flags := mfib_types.MFIB_API_ENTRY_FLAG_NONE
+	if route.Signal {
+		flags |= mfib_types.MFIB_API_ENTRY_FLAG_SIGNAL
+	}
+	if route.Drop {
+		flags |= mfib_types.MFIB_API_ENTRY_FLAG_DROP
+	}
+	if route.Connected {
+		flags |= mfib_types.MFIB_API_ENTRY_FLAG_CONNECTED
+	}
+	if route.AcceptAllInterfaces {
+		flags |= mfib_types.MFIB_API_ENTRY_FLAG_ACCEPT_ALL_ITF
+	}
+	return flags
+}
+
+func buildMfibItfFlags(path domain.MRoutePath) mfib_types.MfibItfFlags {
+	flags := mfib_types.MFIB_API_ITF_FLAG_NONE
+	if path.Accept {
+		flags |= mfib_types.MFIB_API_ITF_FLAG_ACCEPT
+	}
+	if path.Forward {
+		flags |= mfib_types.MFIB_API_ITF_FLAG_FORWARD
+	}
+	if path.SignalPresent {
+		flags |= mfib_types.MFIB_API_ITF_FLAG_SIGNAL_PRESENT
+	}
+	if path.NegateSignal {
+		flags |= mfib_types.MFIB_API_ITF_FLAG_NEGATE_SIGNAL
+	}
+	if path.DontPreserve {
+		flags |= mfib_types.MFIB_API_ITF_FLAG_DONT_PRESERVE
+	}
+	return flags
+}
+
+func ConvertMrouteDetails(details *ip.IPMrouteDetails) domain.MRoute {
+	mroute := details.Route
+	isIPv6 := mroute.Prefix.Af == ip_types.ADDRESS_IP6
+
+	group := unionToIP(mroute.Prefix.GrpAddress, isIPv6)
+	groupLength := mroute.Prefix.GrpAddressLength
+	if groupLength > uint16(hostPrefixLength(isIPv6)) {
+		// (S,G) entries carry the length of source and group together
+		groupLength = uint16(hostPrefixLength(isIPv6))
+	}
+	route := domain.MRoute{
+		VRF: mroute.TableID,
+		Group: domain.IPWithPrefix{
+			Address: group.String(),
+			Prefix:  uint8(groupLength),
+		},
+		RPFID:               mroute.RpfID,
+		Signal:              mroute.EntryFlags&mfib_types.MFIB_API_ENTRY_FLAG_SIGNAL != 0,
+		Drop:                mroute.EntryFlags&mfib_types.MFIB_API_ENTRY_FLAG_DROP != 0,
+		Connected:           mroute.EntryFlags&mfib_types.MFIB_API_ENTRY_FLAG_CONNECTED != 0,
+		AcceptAllInterfaces: mroute.EntryFlags&mfib_types.MFIB_API_ENTRY_FLAG_ACCEPT_ALL_ITF != 0,
+		Paths:               make([]domain.MRoutePath, 0, len(mroute.Paths)),
+	}
+	if source := unionToIP(mroute.Prefix.SrcAddress, isIPv6); !source.IsUnspecified() {
+		route.Source = source
+	}
+
+	for _, p := range mroute.Paths {
+		route.Paths = append(route.Paths, domain.MRoutePath{
+			InterfaceID:   p.Path.SwIfIndex,
+			Accept:        p.ItfFlags&mfib_types.MFIB_API_ITF_FLAG_ACCEPT != 0,
+			Forward:       p.ItfFlags&mfib_types.MFIB_API_ITF_FLAG_FORWARD != 0,
+			SignalPresent: p.ItfFlags&mfib_types.MFIB_API_ITF_FLAG_SIGNAL_PRESENT != 0,
+			NegateSignal:  p.ItfFlags&mfib_types.MFIB_API_ITF_FLAG_NEGATE_SIGNAL != 0,
+			DontPreserve:  p.ItfFlags&mfib_types.MFIB_API_ITF_FLAG_DONT_PRESERVE != 0,
+		})
+	}
+
+	return route
+}
+
+func hostPrefixLength(isIPv6 bool) uint8 {
+	if isIPv6 {
+		return 128
+	}
+	return 32
+}
+
+func unionToIP(un ip_types.AddressUnion, isIPv6 bool) net.IP {
+	if isIPv6 {
+		addr := un.GetIP6()
+		return net.IP(addr[:])
+	}
+	addr := un.GetIP4()
+	return net.IP(addr[:])
+}
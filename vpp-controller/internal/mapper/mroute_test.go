@@ -0,0 +1,94 @@
+package mapper
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/ip"
+)
+
+func TestMrouteRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		route     domain.MRoute
+		wantLen   uint16
+		wantError bool
+	}{
+		{
+			name: "ip4 (*,G)",
+			route: domain.MRoute{
+				VRF:   1,
+				Group: domain.IPWithPrefix{Address: "239.1.1.1", Prefix: 32},
+				Paths: []domain.MRoutePath{{InterfaceID: 1, Accept: true}, {InterfaceID: 2, Forward: true}},
+			},
+			wantLen: 32,
+		},
+		{
+			name: "ip4 (*,G/8)",
+			route: domain.MRoute{
+				Group: domain.IPWithPrefix{Address: "239.0.0.0", Prefix: 8},
+				Paths: []domain.MRoutePath{{InterfaceID: 1, Forward: true}},
+			},
+			wantLen: 8,
+		},
+		{
+			name: "ip4 (S,G)",
+			route: domain.MRoute{
+				Source: net.ParseIP("10.0.0.1").To4(),
+				Group:  domain.IPWithPrefix{Address: "239.1.1.1", Prefix: 32},
+				Paths:  []domain.MRoutePath{{InterfaceID: 1, Accept: true}, {InterfaceID: 2, Forward: true}},
+			},
+			wantLen: 64,
+		},
+		{
+			name: "ip6 (*,G)",
+			route: domain.MRoute{
+				Group: domain.IPWithPrefix{Address: "ff0e::1", Prefix: 128},
+				Paths: []domain.MRoutePath{{InterfaceID: 3, Forward: true}},
+			},
+			wantLen: 128,
+		},
+		{
+			name: "ip6 (S,G)",
+			route: domain.MRoute{
+				Source: net.ParseIP("2001:db8::1"),
+				Group:  domain.IPWithPrefix{Address: "ff0e::1", Prefix: 128},
+				Paths:  []domain.MRoutePath{{InterfaceID: 3, Accept: true}},
+			},
+			wantLen: 256,
+		},
+		{
+			name: "(S,G) with a group range",
+			route: domain.MRoute{
+				Source: net.ParseIP("10.0.0.1").To4(),
+				Group:  domain.IPWithPrefix{Address: "239.0.0.0", Prefix: 8},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			built, err := BuildMroute(tt.route)
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("BuildMroute() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildMroute() error = %v", err)
+			}
+			if built.Prefix.GrpAddressLength != tt.wantLen {
+				t.Errorf("GrpAddressLength = %d, want %d", built.Prefix.GrpAddressLength, tt.wantLen)
+			}
+
+			got := ConvertMrouteDetails(&ip.IPMrouteDetails{Route: built})
+			if !reflect.DeepEqual(got, tt.route) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.route)
+			}
+		})
+	}
+}
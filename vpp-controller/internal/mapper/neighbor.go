@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"errors"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/ethernet_types"
+	"go.fd.io/govpp/binapi/interface_types"
+	"go.fd.io/govpp/binapi/ip_neighbor"
+	"go.fd.io/govpp/binapi/ip_types"
+)
+
+func ConvertNeighbor(neighbor domain.Neighbor) (ip_neighbor.IPNeighbor, error) {
+	if neighbor.IP == nil {
+		return ip_neighbor.IPNeighbor{}, errors.New("neighbor ip is nil")
+	}
+
+	flags := ip_neighbor.IP_API_NEIGHBOR_FLAG_NONE
+	if neighbor.Static {
+		flags |= ip_neighbor.IP_API_NEIGHBOR_FLAG_STATIC
+	}
+	if neighbor.NoFIBEntry {
+		flags |= ip_neighbor.IP_API_NEIGHBOR_FLAG_NO_FIB_ENTRY
+	}
+
+	return ip_neighbor.IPNeighbor{
+		SwIfIndex:  interface_types.InterfaceIndex(neighbor.InterfaceID),
+		Flags:      flags,
+		MacAddress: ethernet_types.NewMacAddress(neighbor.MAC),
+		IPAddress:  ip_types.NewAddress(neighbor.IP),
+	}, nil
+}
+
+func ConvertNeighborDetails(details *ip_neighbor.IPNeighborDetails) domain.Neighbor {
+	neighbor := details.Neighbor
+	return domain.Neighbor{
+		InterfaceID: uint32(neighbor.SwIfIndex),
+		IP:          neighbor.IPAddress.ToIP(),
+		MAC:         neighbor.MacAddress.ToMAC(),
+		Static:      neighbor.Flags&ip_neighbor.IP_API_NEIGHBOR_FLAG_STATIC != 0,
+		NoFIBEntry:  neighbor.Flags&ip_neighbor.IP_API_NEIGHBOR_FLAG_NO_FIB_ENTRY != 0,
+		Age:         details.Age,
+	}
+}
+
+func AddressFamily(isIPv6 bool) ip_types.AddressFamily {
+	if isIPv6 {
+		return ip_types.ADDRESS_IP6
+	}
+	return ip_types.ADDRESS_IP4
+}
@@ -11,11 +11,13 @@ import (
 	"go.fd.io/govpp/binapi/ip_types"
 )
 
-func BuildFibPaths(nextHops []domain.NextHop) ([]fib_types.FibPath, error) {
+const invalidIndex = ^uint32(0)
+
+func BuildFibPaths(nextHops []domain.NextHop, isIPv6 bool) ([]fib_types.FibPath, error) {
 	paths := make([]fib_types.FibPath, 0, len(nextHops))
 
 	for _, nh := range nextHops {
-		path, err := BuildFibPath(nh)
+		path, err := BuildFibPath(nh, isIPv6)
 		if err != nil {
 			return nil, fmt.Errorf("next-hop %v: %w", nh, err)
 		}
@@ -25,32 +27,137 @@ func BuildFibPaths(nextHops []domain.NextHop) ([]fib_types.FibPath, error) {
 	return paths, nil
 }
 
-func BuildFibPath(nh domain.NextHop) (fib_types.FibPath, error) {
+// BuildFibPath converts a next hop into a VPP path, isIPv6 is the address
+// family of the route and is used for paths without a next-hop address
+func BuildFibPath(nh domain.NextHop, isIPv6 bool) (fib_types.FibPath, error) {
+	pathType := nh.Type
 	if nh.Drop {
-		return fib_types.FibPath{
-			Type:   fib_types.FIB_API_PATH_TYPE_DROP,
-			Weight: nh.Weight,
-		}, nil
+		pathType = domain.NextHopDrop
+	}
+
+	proto := fib_types.FIB_API_PATH_NH_PROTO_IP4
+	if isIPv6 {
+		proto = fib_types.FIB_API_PATH_NH_PROTO_IP6
+	}
+
+	path := fib_types.FibPath{
+		SwIfIndex:  invalidIndex,
+		Weight:     nh.Weight,
+		Preference: nh.Preference,
+		Proto:      proto,
+		Flags:      buildFibPathFlags(nh),
+	}
+
+	if len(nh.Labels) > 0 {
+		if pathType != domain.NextHopNormal && pathType != domain.NextHopLookup {
+			return fib_types.FibPath{}, fmt.Errorf("out labels are not supported on %v paths", pathType)
+		}
+		if err := setLabelStack(&path, nh.Labels); err != nil {
+			return fib_types.FibPath{}, err
+		}
 	}
 
+	switch pathType {
+	case domain.NextHopNormal:
+		return buildNormalFibPath(path, nh)
+	case domain.NextHopDrop:
+		path.Type = fib_types.FIB_API_PATH_TYPE_DROP
+	case domain.NextHopLocal:
+		path.Type = fib_types.FIB_API_PATH_TYPE_LOCAL
+	case domain.NextHopUnreachable:
+		path.Type = fib_types.FIB_API_PATH_TYPE_ICMP_UNREACH
+	case domain.NextHopProhibit:
+		path.Type = fib_types.FIB_API_PATH_TYPE_ICMP_PROHIBIT
+	case domain.NextHopLookup:
+		path.Type = fib_types.FIB_API_PATH_TYPE_NORMAL
+		path.TableID = nh.NextVRF
+	case domain.NextHopSourceLookup:
+		path.Type = fib_types.FIB_API_PATH_TYPE_SOURCE_LOOKUP
+		path.TableID = nh.NextVRF
+	case domain.NextHopInterfaceRx:
+		path.Type = fib_types.FIB_API_PATH_TYPE_INTERFACE_RX
+		path.SwIfIndex = nh.IfIndex
+	case domain.NextHopDVR:
+		path.Type = fib_types.FIB_API_PATH_TYPE_DVR
+		path.SwIfIndex = nh.IfIndex
+		path.Proto = fib_types.FIB_API_PATH_NH_PROTO_ETHERNET
+	case domain.NextHopUDPEncap:
+		path.Type = fib_types.FIB_API_PATH_TYPE_UDP_ENCAP
+		path.Nh.ObjID = nh.UDPEncapID
+	default:
+		return fib_types.FibPath{}, fmt.Errorf("unsupported next-hop type: %v", pathType)
+	}
+
+	return path, nil
+}
+
+func buildNormalFibPath(path fib_types.FibPath, nh domain.NextHop) (fib_types.FibPath, error) {
+	path.Type = fib_types.FIB_API_PATH_TYPE_NORMAL
+	path.SwIfIndex = nh.IfIndex
+
 	if nh.IP == nil {
-		return fib_types.FibPath{}, errors.New("next-hop ip is nil")
+		// attached path, packets are resolved on the output interface itself
+		if nh.IfIndex == 0 || nh.IfIndex == invalidIndex {
+			return fib_types.FibPath{}, errors.New("next-hop requires an ip or an output interface")
+		}
+		return path, nil
 	}
 
-	proto := fib_types.FIB_API_PATH_NH_PROTO_IP4
+	path.Proto = fib_types.FIB_API_PATH_NH_PROTO_IP4
 	if nh.IP.To4() == nil {
-		proto = fib_types.FIB_API_PATH_NH_PROTO_IP6
+		path.Proto = fib_types.FIB_API_PATH_NH_PROTO_IP6
 	}
+	path.Nh.Address = ip_types.NewAddress(nh.IP).Un
 
-	return fib_types.FibPath{
-		SwIfIndex: nh.IfIndex,
-		Weight:    nh.Weight,
-		Type:      fib_types.FIB_API_PATH_TYPE_NORMAL,
-		Proto:     proto,
-		Nh: fib_types.FibPathNh{
-			Address: ip_types.NewAddress(nh.IP).Un,
-		},
-	}, nil
+	return path, nil
+}
+
+func setLabelStack(path *fib_types.FibPath, labels []domain.MPLSLabel) error {
+	if len(labels) > domain.MaxLabelStack {
+		return fmt.Errorf("label stack of %d labels exceeds %d", len(labels), domain.MaxLabelStack)
+	}
+	for i, l := range labels {
+		var uniform uint8
+		if l.Uniform {
+			uniform = 1
+		}
+		path.LabelStack[i] = fib_types.FibMplsLabel{
+			IsUniform: uniform,
+			Label:     l.Label,
+			TTL:       l.TTL,
+			Exp:       l.Exp,
+		}
+	}
+	path.NLabels = uint8(len(labels))
+	return nil
+}
+
+func convertLabelStack(path fib_types.FibPath) []domain.MPLSLabel {
+	if path.NLabels == 0 {
+		return nil
+	}
+	n := min(int(path.NLabels), domain.MaxLabelStack)
+	labels := make([]domain.MPLSLabel, 0, n)
+	for _, l := range path.LabelStack[:n] {
+		labels = append(labels, domain.MPLSLabel{
+			Label:   l.Label,
+			TTL:     l.TTL,
+			Exp:     l.Exp,
+			Uniform: l.IsUniform != 0,
+		})
+	}
+	return labels
+}
+
+func buildFibPathFlags(nh domain.NextHop) fib_types.FibPathFlags {
+	flags := fib_types.FIB_API_PATH_FLAG_NONE
+	if nh.ResolveViaHost {
+		flags |= fib_types.FIB_API_PATH_FLAG_RESOLVE_VIA_HOST
+	}
+	if nh.ResolveViaAttached {
+		flags |= fib_types.FIB_API_PATH_FLAG_RESOLVE_VIA_ATTACHED
+	}
+	return flags
 }
 
 func ConvertRouteDetails(details *ip.IPRouteDetails) (domain.Route, error) {
@@ -81,22 +188,45 @@ func ConvertRouteDetails(details *ip.IPRouteDetails) (domain.Route, error) {
 }
 
 func ConvertFibPathToDomainNextHop(path fib_types.FibPath) (domain.NextHop, error) {
-	isDrop := path.Type == fib_types.FIB_API_PATH_TYPE_DROP
-
 	nextHop := domain.NextHop{
-		IfIndex: path.SwIfIndex,
-		Weight:  path.Weight,
-		Drop:    isDrop,
-	}
-
-	if isDrop {
-		return nextHop, nil
+		IfIndex:            path.SwIfIndex,
+		Weight:             path.Weight,
+		Preference:         path.Preference,
+		ResolveViaHost:     path.Flags&fib_types.FIB_API_PATH_FLAG_RESOLVE_VIA_HOST != 0,
+		ResolveViaAttached: path.Flags&fib_types.FIB_API_PATH_FLAG_RESOLVE_VIA_ATTACHED != 0,
+		Labels:             convertLabelStack(path),
 	}
 
-	if path.Type != fib_types.FIB_API_PATH_TYPE_NORMAL {
+	switch path.Type {
+	case fib_types.FIB_API_PATH_TYPE_NORMAL:
+		return convertNormalFibPath(path, nextHop)
+	case fib_types.FIB_API_PATH_TYPE_DROP:
+		nextHop.Type = domain.NextHopDrop
+		nextHop.Drop = true
+	case fib_types.FIB_API_PATH_TYPE_LOCAL:
+		nextHop.Type = domain.NextHopLocal
+	case fib_types.FIB_API_PATH_TYPE_ICMP_UNREACH:
+		nextHop.Type = domain.NextHopUnreachable
+	case fib_types.FIB_API_PATH_TYPE_ICMP_PROHIBIT:
+		nextHop.Type = domain.NextHopProhibit
+	case fib_types.FIB_API_PATH_TYPE_SOURCE_LOOKUP:
+		nextHop.Type = domain.NextHopSourceLookup
+		nextHop.NextVRF = path.TableID
+	case fib_types.FIB_API_PATH_TYPE_INTERFACE_RX:
+		nextHop.Type = domain.NextHopInterfaceRx
+	case fib_types.FIB_API_PATH_TYPE_DVR:
+		nextHop.Type = domain.NextHopDVR
+	case fib_types.FIB_API_PATH_TYPE_UDP_ENCAP:
+		nextHop.Type = domain.NextHopUDPEncap
+		nextHop.UDPEncapID = path.Nh.ObjID
+	default:
 		return domain.NextHop{}, fmt.Errorf("unsupported path type: %v", path.Type)
 	}
 
+	return nextHop, nil
+}
+
+func convertNormalFibPath(path fib_types.FibPath, nextHop domain.NextHop) (domain.NextHop, error) {
 	var ip net.IP
 
 	switch path.Proto {
@@ -114,6 +244,14 @@ func ConvertFibPathToDomainNextHop(path fib_types.FibPath) (domain.NextHop, erro
 		return domain.NextHop{}, fmt.Errorf("unsupported protocol: %v", path.Proto)
 	}
 
+	// no address and no interface is a lookup in another table
+	if ip == nil && path.SwIfIndex == invalidIndex {
+		nextHop.Type = domain.NextHopLookup
+		nextHop.NextVRF = path.TableID
+		return nextHop, nil
+	}
+
+	nextHop.Type = domain.NextHopNormal
 	nextHop.IP = ip
 	return nextHop, nil
 }
@@ -0,0 +1,159 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"go.fd.io/govpp/binapi/interface_types"
+	"go.fd.io/govpp/binapi/ip_types"
+	"go.fd.io/govpp/binapi/sr"
+	"go.fd.io/govpp/binapi/sr_types"
+)
+
+// srBehaviorDT46 is the behavior VPP reports for End.DT46 SIDs in dumps, the
+// sr_behavior API enum ends before it and names the value SR_BEHAVIOR_API_LAST
+const srBehaviorDT46 sr_types.SrBehavior = 10
+
+func BuildSrBehavior(behavior domain.SRv6Behavior) (sr_types.SrBehavior, error) {
+	switch behavior {
+	case domain.SRv6End:
+		return sr_types.SR_BEHAVIOR_API_END, nil
+	case domain.SRv6EndX:
+		return sr_types.SR_BEHAVIOR_API_X, nil
+	case domain.SRv6EndDT4:
+		return sr_types.SR_BEHAVIOR_API_DT4, nil
+	case domain.SRv6EndDT6:
+		return sr_types.SR_BEHAVIOR_API_DT6, nil
+	default:
+		// End.DT46 has no value in the sr_behavior API enum
+		return 0, fmt.Errorf("%v is not supported by the VPP API", behavior)
+	}
+}
+
+func ConvertSrBehavior(behavior sr_types.SrBehavior) (domain.SRv6Behavior, bool) {
+	switch behavior {
+	case sr_types.SR_BEHAVIOR_API_END:
+		return domain.SRv6End, true
+	case sr_types.SR_BEHAVIOR_API_X:
+		return domain.SRv6EndX, true
+	case sr_types.SR_BEHAVIOR_API_DT4:
+		return domain.SRv6EndDT4, true
+	case sr_types.SR_BEHAVIOR_API_DT6:
+		return domain.SRv6EndDT6, true
+	case srBehaviorDT46:
+		return domain.SRv6EndDT46, true
+	default:
+		return 0, false
+	}
+}
+
+func ConvertSrLocalsidDetails(details *sr.SrLocalsidsDetails) (domain.SRv6LocalSID, bool) {
+	behavior, ok := ConvertSrBehavior(details.Behavior)
+	if !ok {
+		return domain.SRv6LocalSID{}, false
+	}
+
+	sid := domain.SRv6LocalSID{
+		SID:      details.Addr.ToIP(),
+		Behavior: behavior,
+		PSP:      details.EndPsp,
+		Table:    details.FibTable,
+	}
+	switch behavior {
+	case domain.SRv6EndX:
+		sid.InterfaceID = details.XconnectIfaceOrVrfTable
+		sid.NextHop = details.XconnectNhAddr.ToIP()
+	case domain.SRv6EndDT4, domain.SRv6EndDT6, domain.SRv6EndDT46:
+		sid.VRF = details.XconnectIfaceOrVrfTable
+	}
+	return sid, true
+}
+
+func BuildSrv6SidList(list domain.SRv6SegmentList) (sr.Srv6SidList, error) {
+	if len(list.Segments) == 0 {
+		return sr.Srv6SidList{}, errors.New("segment list is empty")
+	}
+	if len(list.Segments) > domain.MaxSegments {
+		return sr.Srv6SidList{}, fmt.Errorf("segment list of %d segments exceeds %d",
+			len(list.Segments), domain.MaxSegments)
+	}
+
+	sidList := sr.Srv6SidList{
+		NumSids: uint8(len(list.Segments)),
+		Weight:  list.Weight,
+	}
+	for i, segment := range list.Segments {
+		if !IsIPv6(segment) {
+			return sr.Srv6SidList{}, fmt.Errorf("segment %v is not an IPv6 address", segment)
+		}
+		sidList.Sids[i] = ip_types.NewIP6Address(segment)
+	}
+	return sidList, nil
+}
+
+func ConvertSrPolicyDetails(details *sr.SrPoliciesWithSlIndexDetails) domain.SRv6Policy {
+	policy := domain.SRv6Policy{
+		BSID:         details.Bsid.ToIP(),
+		Encap:        details.IsEncap,
+		Spray:        details.IsSpray,
+		Table:        details.FibTable,
+		SegmentLists: make([]domain.SRv6SegmentList, 0, len(details.SidLists)),
+	}
+	for _, list := range details.SidLists {
+		n := min(int(list.NumSids), domain.MaxSegments)
+		segments := make([]net.IP, 0, n)
+		for _, sid := range list.Sids[:n] {
+			segments = append(segments, sid.ToIP())
+		}
+		policy.SegmentLists = append(policy.SegmentLists, domain.SRv6SegmentList{
+			Index:    list.SlIndex,
+			Weight:   list.Weight,
+			Segments: segments,
+		})
+	}
+	return policy
+}
+
+func BuildSrSteering(steering domain.SRv6Steering, isDel bool) (*sr.SrSteeringAddDel, error) {
+	addr := steering.Prefix.ToNetIP()
+	if addr == nil {
+		return nil, fmt.Errorf("invalid steering prefix: %s", steering.Prefix.Address)
+	}
+	trafficType := sr_types.SR_STEER_API_IPV4
+	if steering.Prefix.IsIPv6() {
+		trafficType = sr_types.SR_STEER_API_IPV6
+	}
+
+	return &sr.SrSteeringAddDel{
+		IsDel:    isDel,
+		BsidAddr: ip_types.NewIP6Address(steering.BSID),
+		TableID:  steering.VRF,
+		Prefix: ip_types.Prefix{
+			Address: ip_types.NewAddress(addr),
+			Len:     steering.Prefix.Prefix,
+		},
+		SwIfIndex:   interface_types.InterfaceIndex(^uint32(0)),
+		TrafficType: trafficType,
+	}, nil
+}
+
+func ConvertSrSteeringDetails(details *sr.SrSteeringPolDetails) (domain.SRv6Steering, bool) {
+	if details.TrafficType == sr_types.SR_STEER_API_L2 {
+		return domain.SRv6Steering{}, false
+	}
+	return domain.SRv6Steering{
+		BSID: details.Bsid.ToIP(),
+		VRF:  details.FibTable,
+		Prefix: domain.IPWithPrefix{
+			Address: details.Prefix.Address.ToIP().String(),
+			Prefix:  details.Prefix.Len,
+		},
+	}, true
+}
+
+// IsIPv6 reports whether ip is a non IPv4-mapped IPv6 address
+func IsIPv6(ip net.IP) bool {
+	return ip != nil && ip.To4() == nil && ip.To16() != nil
+}
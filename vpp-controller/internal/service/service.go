@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"net"
+	"net/netip"
 
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 	interfaces "go.fd.io/govpp/binapi/interface"
@@ -10,7 +12,13 @@ import (
 type Info interface {
 	GetVersion(ctx context.Context) (domain.Version, error)
 }
+type InterfaceResolver interface {
+	ResolveInterface(ctx context.Context, ref string) (uint32, error)
+}
+
 type Interface interface {
+	InterfaceResolver
+	InitNameCache(ctx context.Context) error
 	List(ctx context.Context) ([]interfaces.SwInterfaceDetails, error)
 	CreateLoopback(ctx context.Context) (interfaces.CreateLoopbackReply, error)
 	DeleteLoopback(ctx context.Context, ifIndex uint32) error
@@ -19,13 +27,30 @@ type Interface interface {
 	AttachACL(ctx context.Context, ifIndex uint32, aclID uint32, dir uint8) error
 	DetachACL(ctx context.Context, ifIndex uint32, aclID uint32, dir uint8) error
 	ListACL(ctx context.Context, ifIndex uint32) (domain.ACLInterfaceList, error)
+	SetACLList(ctx context.Context, ifIndex uint32, input, output []uint32) (domain.ACLInterfaceList, error)
+	InsertACL(ctx context.Context, ifIndex uint32, aclID uint32, dir uint8, position int) (domain.ACLInterfaceList, error)
+	SetUnnumbered(ctx context.Context, ifIndex uint32, ipIfIndex uint32) error
+	DeleteUnnumbered(ctx context.Context, ifIndex uint32) error
+	ListUnnumbered(ctx context.Context) ([]domain.UnnumberedInterface, error)
+	SetURPF(ctx context.Context, setting domain.URPF) error
+	ListURPF(ctx context.Context, ifIndex uint32) ([]domain.URPF, error)
+	SetTag(ctx context.Context, ifIndex uint32, tag string) error
+	SetMetadata(ctx context.Context, ifIndex uint32, metadata domain.InterfaceMetadata) error
+	GetMetadata(ifIndex uint32) (domain.InterfaceMetadata, bool)
+	DeleteMetadata(ifIndex uint32) error
 }
 
 type Route interface {
 	AddRoute(ctx context.Context, route *domain.Route) error
 	DeleteRoute(ctx context.Context, route *domain.Route) error
+	ReplaceRoute(ctx context.Context, route *domain.Route) error
+	ModifyRoutePaths(ctx context.Context, dst domain.IPWithPrefix, vrf uint32, add, remove []domain.NextHop) (domain.Route, error)
+	DeleteRoutePrefix(ctx context.Context, dst domain.IPWithPrefix, vrf uint32) error
 	ListRoutes(ctx context.Context, vrf uint32) ([]domain.Route, error)
+	WalkRoutes(ctx context.Context, vrf uint32, filter domain.RouteFilter, fn func(domain.Route) error) error
+	ListRoutesPage(ctx context.Context, vrf uint32, filter domain.RouteFilter, after netip.Prefix, limit int) ([]domain.Route, bool, error)
 	GetRoute(ctx context.Context, dst domain.IPWithPrefix, vrf uint32) (domain.Route, error)
+	LookupRoute(ctx context.Context, addr net.IP, vrf uint32, recursive bool) (domain.RouteLookup, error)
 }
 
 type VRF interface {
@@ -33,6 +58,14 @@ type VRF interface {
 	CreateVRF(ctx context.Context, id uint32, name string) error
 	DeleteVRF(ctx context.Context, id uint32) error
 	ListVRF(ctx context.Context) ([]domain.VRF, error)
+	SetFlowHash(ctx context.Context, vrf uint32, isIPv6 bool, flowHash domain.FlowHash) error
+	GetFlowHash(vrf uint32) (*domain.FlowHash, *domain.FlowHash, error)
+}
+
+type VRFLeak interface {
+	CreateVRFLeak(ctx context.Context, leak domain.VRFLeak) (uint32, error)
+	ListVRFLeaks(vrf uint32) []domain.VRFLeak
+	DeleteVRFLeak(ctx context.Context, vrf uint32, id uint32) error
 }
 
 type ACL interface {
@@ -40,11 +73,110 @@ type ACL interface {
 	Update(ctx context.Context, id domain.AclID, rules []domain.ACLRule) error
 	Delete(ctx context.Context, id domain.AclID) error
 	List(ctx context.Context) ([]domain.ACLInfo, error)
+	Get(ctx context.Context, id domain.AclID) (domain.ACLInfo, error)
+	Import(ctx context.Context, chains []domain.ACLChain) ([]domain.AclID, error)
+	Analyze(ctx context.Context, id domain.AclID) (domain.ACLInfo, []domain.ACLFinding, error)
+	Simulate(ctx context.Context, ifIndex uint32, input bool, pkt domain.ACLPacket) (domain.ACLSimulation, error)
+	GetSessionStats(ctx context.Context, ifIndex uint32) (domain.ACLSessionStats, error)
+	ClearSessions(ctx context.Context) error
+	ClearInterfaceSessions(ctx context.Context, ifIndex uint32) error
+	SetSessionTimeouts(ctx context.Context, timeouts domain.ACLSessionTimeouts) error
+	GetSessionTimeouts(ctx context.Context) (domain.ACLSessionTimeouts, error)
+	EnableCounters(ctx context.Context) error
+	GetStats(ctx context.Context, id domain.AclID) (domain.ACLStats, error)
+	ListStats(ctx context.Context, acls []domain.ACLInfo) ([]domain.ACLStats, error)
+	ResetStats(ctx context.Context, id domain.AclID) error
+}
+
+type ACLGroup interface {
+	CreateACL(ctx context.Context, name string, rules []domain.ACLGroupRule) (domain.AclID, error)
+	UpdateACL(ctx context.Context, id domain.AclID, rules []domain.ACLGroupRule) error
+	DeleteACL(ctx context.Context, id domain.AclID) error
+	SetAddressGroup(ctx context.Context, group domain.ACLAddressGroup) ([]domain.AclID, error)
+	DeleteAddressGroup(name string) error
+	GetAddressGroup(name string) (domain.ACLAddressGroup, []domain.AclID, error)
+	ListAddressGroups() []domain.ACLAddressGroup
+	SetPortGroup(ctx context.Context, group domain.ACLPortGroup) ([]domain.AclID, error)
+	DeletePortGroup(name string) error
+	GetPortGroup(name string) (domain.ACLPortGroup, []domain.AclID, error)
+	ListPortGroups() []domain.ACLPortGroup
+}
+
+type MACIP interface {
+	Create(ctx context.Context, name string, rules []domain.MACIPRule) (domain.AclID, error)
+	Replace(ctx context.Context, id domain.AclID, rules []domain.MACIPRule) error
+	Delete(ctx context.Context, id domain.AclID) error
+	List(ctx context.Context) ([]domain.MACIPACL, error)
+	Get(ctx context.Context, id domain.AclID) (domain.MACIPACL, error)
+	Assign(ctx context.Context, ifIndex uint32, id domain.AclID) error
+	Unassign(ctx context.Context, ifIndex uint32) error
+	ListAssignments(ctx context.Context) ([]domain.MACIPAssignment, error)
+}
+
+type Neighbor interface {
+	AddNeighbor(ctx context.Context, neighbor domain.Neighbor) error
+	DeleteNeighbor(ctx context.Context, neighbor domain.Neighbor) error
+	ListNeighbors(ctx context.Context, filter domain.NeighborFilter) ([]domain.Neighbor, error)
+	FlushNeighbors(ctx context.Context, ifIndex uint32, isIPv6 bool) error
+	GetNeighborConfig(ctx context.Context, isIPv6 bool) (domain.NeighborConfig, error)
+	SetNeighborConfig(ctx context.Context, config domain.NeighborConfig) error
+}
+
+type IP6ND interface {
+	EnableIPv6(ctx context.Context, ifIndex uint32, enable bool) error
+	SetRAConfig(ctx context.Context, ifIndex uint32, config domain.RAConfig) error
+	AddRAPrefix(ctx context.Context, ifIndex uint32, prefix domain.RAPrefix) error
+	DeleteRAPrefix(ctx context.Context, ifIndex uint32, prefix domain.IPWithPrefix) error
+	ListRA(ctx context.Context, ifIndex uint32) ([]domain.RAInfo, error)
+}
+
+type MRoute interface {
+	AddMRoute(ctx context.Context, route domain.MRoute) error
+	DeleteMRoute(ctx context.Context, route domain.MRoute) error
+	ListMRoutes(ctx context.Context, vrf uint32) ([]domain.MRoute, error)
+}
+
+type MPLS interface {
+	EnableMPLS(ctx context.Context, ifIndex uint32, enable bool) error
+	ListMPLSInterfaces(ctx context.Context) ([]uint32, error)
+	CreateMPLSTable(ctx context.Context, table domain.MPLSTable) error
+	DeleteMPLSTable(ctx context.Context, id uint32) error
+	ListMPLSTables(ctx context.Context) ([]domain.MPLSTable, error)
+	AddMPLSRoute(ctx context.Context, route domain.MPLSRoute) error
+	DeleteMPLSRoute(ctx context.Context, table, label uint32, eos bool) error
+	ListMPLSRoutes(ctx context.Context, table uint32) ([]domain.MPLSRoute, error)
+}
+
+type SRv6 interface {
+	AddLocalSID(ctx context.Context, sid domain.SRv6LocalSID) error
+	DeleteLocalSID(ctx context.Context, sid net.IP, table uint32) error
+	ListLocalSIDs(ctx context.Context) ([]domain.SRv6LocalSID, error)
+	CreatePolicy(ctx context.Context, policy domain.SRv6Policy) error
+	DeletePolicy(ctx context.Context, bsid net.IP) error
+	ListPolicies(ctx context.Context) ([]domain.SRv6Policy, error)
+	GetPolicy(ctx context.Context, bsid net.IP) (domain.SRv6Policy, error)
+	AddSegmentList(ctx context.Context, bsid net.IP, list domain.SRv6SegmentList) (domain.SRv6Policy, error)
+	DeleteSegmentList(ctx context.Context, bsid net.IP, index uint32) error
+	AddSteering(ctx context.Context, steering domain.SRv6Steering) error
+	DeleteSteering(ctx context.Context, steering domain.SRv6Steering) error
+	ListSteering(ctx context.Context) ([]domain.SRv6Steering, error)
+	SetEncapSource(ctx context.Context, addr net.IP) error
+}
+
+type ABF interface {
+	CreatePolicy(ctx context.Context, policy domain.ABFPolicy) error
+	DeletePolicy(ctx context.Context, id uint32) error
+	GetPolicy(ctx context.Context, id uint32) (domain.ABFPolicy, error)
+	ListPolicies(ctx context.Context) ([]domain.ABFPolicy, error)
+	Attach(ctx context.Context, attachment domain.ABFAttachment) error
+	Detach(ctx context.Context, attachment domain.ABFAttachment) error
+	ListAttachments(ctx context.Context, filter domain.ABFAttachmentFilter) ([]domain.ABFAttachment, error)
 }
 
 type IP interface {
 	Route
 	VRF
+	VRFLeak
 }
 
 type Services struct {
@@ -52,13 +184,29 @@ type Services struct {
 	Interface Interface
 	IP        IP
 	ACL       ACL
+	Neighbor  Neighbor
+	IP6ND     IP6ND
+	MRoute    MRoute
+	MPLS      MPLS
+	SRv6      SRv6
+	ABF       ABF
+	MACIP     MACIP
+	ACLGroup  ACLGroup
 }
 
-func NewServices(info Info, inter Interface, IPService IP, acl ACL) *Services {
+func NewServices(info Info, inter Interface, IPService IP, acl ACL, neighbor Neighbor, ip6nd IP6ND, mroute MRoute, mpls MPLS, srv6 SRv6, abf ABF, macip MACIP, aclGroup ACLGroup) *Services {
 	return &Services{
 		info,
 		inter,
 		IPService,
 		acl,
+		neighbor,
+		ip6nd,
+		mroute,
+		mpls,
+		srv6,
+		abf,
+		macip,
+		aclGroup,
 	}
 }
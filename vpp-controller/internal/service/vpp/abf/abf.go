@@ -0,0 +1,181 @@
+package abf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
+	aclServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/acl"
+	"go.fd.io/govpp/api"
+	"go.fd.io/govpp/binapi/abf"
+)
+
+var (
+	ErrNotFound           = errors.New("abf policy not found")
+	ErrAttachmentNotFound = errors.New("abf attachment not found")
+	ErrExists             = errors.New("abf policy already exists")
+	ErrACLNotFound        = errors.New("acl not found")
+	ErrInUse              = errors.New("abf policy is attached to interfaces")
+	ErrNoPaths            = errors.New("abf policy requires at least one path")
+)
+
+// ACLGetter looks up an ACL by index
+type ACLGetter interface {
+	Get(ctx context.Context, id domain.AclID) (domain.ACLInfo, error)
+}
+
+type Service struct {
+	client *vpp.Client
+	acls   ACLGetter
+}
+
+func NewService(client *vpp.Client, acls ACLGetter) *Service {
+	return &Service{client: client, acls: acls}
+}
+
+// CreatePolicy binds an existing ACL to forwarding paths, VPP merges the
+// paths of an add into an existing policy so the ID must be unused
+func (s *Service) CreatePolicy(ctx context.Context, policy domain.ABFPolicy) error {
+	if len(policy.NextHops) == 0 {
+		return ErrNoPaths
+	}
+	if _, err := s.acls.Get(ctx, policy.ACLID); errors.Is(err, aclServ.ErrNotFound) {
+		return fmt.Errorf("abf policy %d acl %d: %w: %w", policy.ID, policy.ACLID, ErrACLNotFound, err)
+	} else if err != nil {
+		return fmt.Errorf("abf policy %d get acl %d: %w", policy.ID, policy.ACLID, err)
+	}
+	if _, err := s.GetPolicy(ctx, policy.ID); err == nil {
+		return fmt.Errorf("abf policy %d: %w", policy.ID, ErrExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	abfPolicy, err := mapper.BuildAbfPolicy(policy)
+	if err != nil {
+		return err
+	}
+	req := &abf.AbfPolicyAddDel{IsAdd: true, Policy: abfPolicy}
+	_, err = vpp.DoRequest[*abf.AbfPolicyAddDel, *abf.AbfPolicyAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("add abf policy %d: %w", policy.ID, err)
+	}
+	return nil
+}
+
+// DeletePolicy removes a policy that is no longer attached to any interface
+func (s *Service) DeletePolicy(ctx context.Context, id uint32) error {
+	policy, err := s.GetPolicy(ctx, id)
+	if err != nil {
+		return err
+	}
+	attachments, err := s.ListAttachments(ctx, domain.ABFAttachmentFilter{PolicyID: &id})
+	if err != nil {
+		return err
+	}
+	if len(attachments) > 0 {
+		return fmt.Errorf("abf policy %d has %d attachments: %w", id, len(attachments), ErrInUse)
+	}
+
+	// the delete removes the given paths and the policy with its last path
+	abfPolicy, err := mapper.BuildAbfPolicy(policy)
+	if err != nil {
+		return err
+	}
+	req := &abf.AbfPolicyAddDel{IsAdd: false, Policy: abfPolicy}
+	_, err = vpp.DoRequest[*abf.AbfPolicyAddDel, *abf.AbfPolicyAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("delete abf policy %d: %w", id, err)
+	}
+	return nil
+}
+
+func (s *Service) GetPolicy(ctx context.Context, id uint32) (domain.ABFPolicy, error) {
+	policies, err := s.ListPolicies(ctx)
+	if err != nil {
+		return domain.ABFPolicy{}, err
+	}
+	for _, policy := range policies {
+		if policy.ID == id {
+			return policy, nil
+		}
+	}
+	return domain.ABFPolicy{}, fmt.Errorf("abf policy %d: %w", id, ErrNotFound)
+}
+
+func (s *Service) ListPolicies(ctx context.Context) ([]domain.ABFPolicy, error) {
+	converter := func(msg api.Message) (domain.ABFPolicy, bool) {
+		details, ok := msg.(*abf.AbfPolicyDetails)
+		if !ok {
+			return domain.ABFPolicy{}, false
+		}
+		policy, err := mapper.ConvertAbfPolicy(details.Policy)
+		if err != nil {
+			return domain.ABFPolicy{}, false
+		}
+		return policy, true
+	}
+
+	policies, err := vpp.Dump(ctx, s.client, &abf.AbfPolicyDump{}, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump abf policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Attach applies the policy to the interface for one address family
+func (s *Service) Attach(ctx context.Context, attachment domain.ABFAttachment) error {
+	if _, err := s.GetPolicy(ctx, attachment.PolicyID); err != nil {
+		return err
+	}
+	return s.attachAddDel(ctx, attachment, true)
+}
+
+func (s *Service) Detach(ctx context.Context, attachment domain.ABFAttachment) error {
+	filter := domain.ABFAttachmentFilter{
+		InterfaceID: &attachment.InterfaceID,
+		PolicyID:    &attachment.PolicyID,
+		IPv6:        &attachment.IPv6,
+	}
+	attachments, err := s.ListAttachments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(attachments) == 0 {
+		return fmt.Errorf("policy %d on interface %d: %w",
+			attachment.PolicyID, attachment.InterfaceID, ErrAttachmentNotFound)
+	}
+	return s.attachAddDel(ctx, attachments[0], false)
+}
+
+func (s *Service) attachAddDel(ctx context.Context, attachment domain.ABFAttachment, isAdd bool) error {
+	req := &abf.AbfItfAttachAddDel{
+		IsAdd:  isAdd,
+		Attach: mapper.BuildAbfItfAttach(attachment),
+	}
+	_, err := vpp.DoRequest[*abf.AbfItfAttachAddDel, *abf.AbfItfAttachAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("abf attach (is_add=%t) policy %d on interface %d: %w",
+			isAdd, attachment.PolicyID, attachment.InterfaceID, err)
+	}
+	return nil
+}
+
+func (s *Service) ListAttachments(ctx context.Context, filter domain.ABFAttachmentFilter) ([]domain.ABFAttachment, error) {
+	converter := func(msg api.Message) (domain.ABFAttachment, bool) {
+		details, ok := msg.(*abf.AbfItfAttachDetails)
+		if !ok {
+			return domain.ABFAttachment{}, false
+		}
+		attachment := mapper.ConvertAbfItfAttach(details.Attach)
+		return attachment, filter.Match(attachment)
+	}
+
+	attachments, err := vpp.Dump(ctx, s.client, &abf.AbfItfAttachDump{}, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump abf attachments: %w", err)
+	}
+	return attachments, nil
+}
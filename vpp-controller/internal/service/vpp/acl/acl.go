@@ -2,8 +2,11 @@ package acl
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
+	"github.com/NikolayStepanov/RapidVPP/internal/acleval"
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
 	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
@@ -11,8 +14,30 @@ import (
 	"go.fd.io/govpp/binapi/acl"
 )
 
+var (
+	ErrNotFound         = errors.New("acl not found")
+	ErrTimeoutsUnknown  = errors.New("acl session timeouts unknown")
+	ErrStatsUnavailable = errors.New("acl counters unavailable")
+)
+
+// InterfaceACLLister lists the ACLs applied to an interface in order
+type InterfaceACLLister interface {
+	ListACL(ctx context.Context, ifIndex uint32) (domain.ACLInterfaceList, error)
+}
+
 type Service struct {
 	client *vpp.Client
+	// stats is nil when the stats segment or the counters are unavailable
+	stats      *vpp.StatsClient
+	interfaces InterfaceACLLister
+	// the ACL plugin has no API to read session timeouts back, the values
+	// configured by this controller are kept to report them
+	timeoutsMu sync.RWMutex
+	timeouts   domain.ACLSessionTimeouts
+	// the stats segment counters cannot be cleared, a reset records the
+	// current values which are subtracted on read
+	baselineMu sync.Mutex
+	baselines  map[domain.AclID][]domain.ACLRuleCounter
 }
 
 func (s *Service) Create(ctx context.Context, name string, rules []domain.ACLRule) (domain.AclID, error) {
@@ -56,10 +81,31 @@ func (s *Service) Update(ctx context.Context, id domain.AclID, rules []domain.AC
 	if err != nil {
 		return fmt.Errorf("update acl operation failed: %w", err)
 	}
+	// replacing the rules resets the counters of the ACL in VPP
+	s.dropBaseline(id)
 
 	return nil
 }
 
+// Import creates one ACL per chain, the ACLs created before a failure are
+// deleted again
+func (s *Service) Import(ctx context.Context, chains []domain.ACLChain) ([]domain.AclID, error) {
+	ids := make([]domain.AclID, 0, len(chains))
+	for _, chain := range chains {
+		id, err := s.Create(ctx, chain.Name, chain.Rules)
+		if err != nil {
+			for _, created := range ids {
+				if delErr := s.Delete(ctx, created); delErr != nil {
+					err = errors.Join(err, delErr)
+				}
+			}
+			return nil, fmt.Errorf("import chain %s: %w", chain.Name, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 func (s *Service) Delete(ctx context.Context, id domain.AclID) error {
 	req := &acl.ACLDel{
 		ACLIndex: uint32(id),
@@ -69,13 +115,78 @@ func (s *Service) Delete(ctx context.Context, id domain.AclID) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete ACL %d: %w", id, err)
 	}
+	s.dropBaseline(id)
 
 	return nil
 }
 
 func (s *Service) List(ctx context.Context) ([]domain.ACLInfo, error) {
+	return s.dump(ctx, 0xFFFFFFFF)
+}
+
+// Get returns a single ACL, ErrNotFound when the index is not in use
+func (s *Service) Get(ctx context.Context, id domain.AclID) (domain.ACLInfo, error) {
+	acls, err := s.dump(ctx, uint32(id))
+	if err != nil {
+		return domain.ACLInfo{}, err
+	}
+	for _, info := range acls {
+		if info.ID == id {
+			return info, nil
+		}
+	}
+	return domain.ACLInfo{}, fmt.Errorf("acl %d: %w", id, ErrNotFound)
+}
+
+// Analyze reports shadowed, redundant and conflicting rules of the ACL
+func (s *Service) Analyze(ctx context.Context, id domain.AclID) (domain.ACLInfo, []domain.ACLFinding, error) {
+	info, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.ACLInfo{}, nil, err
+	}
+	findings, err := acleval.Analyze(info.Rules)
+	if err != nil {
+		return domain.ACLInfo{}, nil, fmt.Errorf("analyze acl %d: %w", id, err)
+	}
+	return info, findings, nil
+}
+
+// Simulate checks the packet against the ACLs applied to the input or
+// output of the interface
+func (s *Service) Simulate(ctx context.Context, ifIndex uint32, input bool, pkt domain.ACLPacket) (domain.ACLSimulation, error) {
+	applied, err := s.interfaces.ListACL(ctx, ifIndex)
+	if err != nil {
+		return domain.ACLSimulation{}, fmt.Errorf("list acls of interface %d: %w", ifIndex, err)
+	}
+	ids := applied.OutputACLs
+	if input {
+		ids = applied.InputACLs
+	}
+
+	acls := make([]domain.ACLInfo, 0, len(ids))
+	if len(ids) > 0 {
+		all, err := s.List(ctx)
+		if err != nil {
+			return domain.ACLSimulation{}, err
+		}
+		byID := make(map[domain.AclID]domain.ACLInfo, len(all))
+		for _, info := range all {
+			byID[info.ID] = info
+		}
+		for _, id := range ids {
+			info, ok := byID[domain.AclID(id)]
+			if !ok {
+				return domain.ACLSimulation{}, fmt.Errorf("acl %d applied to interface %d: %w", id, ifIndex, ErrNotFound)
+			}
+			acls = append(acls, info)
+		}
+	}
+	return acleval.Simulate(acls, pkt)
+}
+
+func (s *Service) dump(ctx context.Context, index uint32) ([]domain.ACLInfo, error) {
 	request := &acl.ACLDump{
-		ACLIndex: 0xFFFFFFFF,
+		ACLIndex: index,
 	}
 
 	converter := func(msg api.Message) (domain.ACLInfo, bool) {
@@ -98,7 +209,92 @@ func (s *Service) List(ctx context.Context) ([]domain.ACLInfo, error) {
 	return vpp.Dump(ctx, s.client, request, converter)
 }
 
-func NewService(client *vpp.Client) *Service {
-	return &Service{client: client}
+func NewService(client *vpp.Client, stats *vpp.StatsClient, interfaces InterfaceACLLister) *Service {
+	return &Service{
+		client:     client,
+		stats:      stats,
+		interfaces: interfaces,
+		baselines:  make(map[domain.AclID][]domain.ACLRuleCounter),
+	}
+}
+
+// GetSessionStats returns the reflexive session counters of the interface,
+// sessions are only available through the debug CLI
+func (s *Service) GetSessionStats(ctx context.Context, ifIndex uint32) (domain.ACLSessionStats, error) {
+	output, err := vpp.RunCLI(ctx, s.client, "show acl-plugin sessions")
+	if err != nil {
+		return domain.ACLSessionStats{}, fmt.Errorf("show acl sessions: %w", err)
+	}
+	stats, ok := mapper.ParseACLSessionStats(output)[ifIndex]
+	if !ok {
+		return domain.ACLSessionStats{InterfaceID: ifIndex}, nil
+	}
+	return stats, nil
+}
+
+// ClearSessions deletes the reflexive sessions of all interfaces
+func (s *Service) ClearSessions(ctx context.Context) error {
+	if _, err := vpp.RunCLI(ctx, s.client, "clear acl-plugin sessions"); err != nil {
+		return fmt.Errorf("clear acl sessions: %w", err)
+	}
+	return nil
+}
+
+// ClearInterfaceSessions deletes the reflexive sessions of one interface
+func (s *Service) ClearInterfaceSessions(ctx context.Context, ifIndex uint32) error {
+	cmd := fmt.Sprintf("clear acl-plugin sessions sw_if_index %d", ifIndex)
+	output, err := vpp.RunCLI(ctx, s.client, cmd)
+	if err != nil {
+		return fmt.Errorf("clear acl sessions of interface %d: %w", ifIndex, err)
+	}
+	if output != "" {
+		return fmt.Errorf("clear acl sessions of interface %d: %s", ifIndex, output)
+	}
+	return nil
+}
+
+func (s *Service) SetSessionTimeouts(ctx context.Context, timeouts domain.ACLSessionTimeouts) error {
+	s.timeoutsMu.Lock()
+	defer s.timeoutsMu.Unlock()
+
+	// applied values are recorded one by one so the cache follows VPP on a partial failure
+	commands := []struct {
+		name    string
+		value   uint32
+		current *uint32
+	}{
+		{"udp idle", timeouts.UDPIdle, &s.timeouts.UDPIdle},
+		{"tcp idle", timeouts.TCPIdle, &s.timeouts.TCPIdle},
+		{"tcp transient", timeouts.TCPTransient, &s.timeouts.TCPTransient},
+	}
+	for _, c := range commands {
+		if c.value == 0 {
+			return fmt.Errorf("acl session timeout %s must be positive", c.name)
+		}
+	}
+	for _, c := range commands {
+		cmd := fmt.Sprintf("set acl-plugin session timeout %s %d", c.name, c.value)
+		output, err := vpp.RunCLI(ctx, s.client, cmd)
+		if err != nil {
+			return fmt.Errorf("set acl session timeout %s: %w", c.name, err)
+		}
+		if output != "" {
+			// the CLI reports parse and range errors as text with a zero retval
+			return fmt.Errorf("set acl session timeout %s: %s", c.name, output)
+		}
+		*c.current = c.value
+	}
+	return nil
+}
 
+// GetSessionTimeouts returns the timeouts configured since the controller
+// started, neither the API nor the CLI of VPP reports them. ErrTimeoutsUnknown
+// is returned until all of them were set.
+func (s *Service) GetSessionTimeouts(ctx context.Context) (domain.ACLSessionTimeouts, error) {
+	s.timeoutsMu.RLock()
+	defer s.timeoutsMu.RUnlock()
+	if s.timeouts.UDPIdle == 0 || s.timeouts.TCPIdle == 0 || s.timeouts.TCPTransient == 0 {
+		return domain.ACLSessionTimeouts{}, ErrTimeoutsUnknown
+	}
+	return s.timeouts, nil
 }
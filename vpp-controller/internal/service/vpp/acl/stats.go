@@ -0,0 +1,146 @@
+package acl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"go.fd.io/govpp/adapter"
+	"go.fd.io/govpp/binapi/acl"
+)
+
+// the ACL plugin publishes one combined counter vector per ACL named
+// /acl/<index>/matches, indexed by worker thread and rule
+const statsPattern = `^/acl/[0-9]+/matches$`
+
+// EnableCounters turns on the per-rule counters of the ACL plugin, VPP only
+// updates the stats segment while they are enabled. On failure the counters
+// are reported unavailable; it is called before the server starts.
+func (s *Service) EnableCounters(ctx context.Context) error {
+	if s.stats == nil {
+		return nil
+	}
+	req := &acl.ACLStatsIntfCountersEnable{
+		Enable: true,
+	}
+	_, err := vpp.DoRequest[*acl.ACLStatsIntfCountersEnable, *acl.ACLStatsIntfCountersEnableReply](s.client, ctx, req)
+	if err != nil {
+		s.stats = nil
+		return fmt.Errorf("enable acl counters: %w", err)
+	}
+	return nil
+}
+
+// GetStats returns the hit counters of every rule of the ACL
+func (s *Service) GetStats(ctx context.Context, id domain.AclID) (domain.ACLStats, error) {
+	if s.stats == nil {
+		return domain.ACLStats{}, ErrStatsUnavailable
+	}
+	info, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.ACLStats{}, err
+	}
+	stat, _, err := s.stats.CombinedCounter(statsName(id))
+	if err != nil {
+		return domain.ACLStats{}, fmt.Errorf("read acl %d counters: %w", id, err)
+	}
+
+	s.baselineMu.Lock()
+	defer s.baselineMu.Unlock()
+	return domain.ACLStats{ID: id, Rules: s.sinceReset(id, reduce(stat, len(info.Rules)))}, nil
+}
+
+// ListStats returns the hit counters of the given ACLs, ACLs missing from
+// the stats segment report zero for every rule
+func (s *Service) ListStats(ctx context.Context, acls []domain.ACLInfo) ([]domain.ACLStats, error) {
+	if s.stats == nil {
+		return nil, ErrStatsUnavailable
+	}
+	counters, err := s.stats.CombinedCounters(statsPattern)
+	if err != nil {
+		return nil, fmt.Errorf("read acl counters: %w", err)
+	}
+
+	s.baselineMu.Lock()
+	defer s.baselineMu.Unlock()
+	stats := make([]domain.ACLStats, 0, len(acls))
+	for _, info := range acls {
+		rules := reduce(counters[statsName(info.ID)], len(info.Rules))
+		stats = append(stats, domain.ACLStats{ID: info.ID, Rules: s.sinceReset(info.ID, rules)})
+	}
+	return stats, nil
+}
+
+// ResetStats sets the hit counters of the ACL back to zero. The baseline is
+// kept in memory, a controller restart brings back the counts since the
+// ACL was created.
+func (s *Service) ResetStats(ctx context.Context, id domain.AclID) error {
+	if s.stats == nil {
+		return ErrStatsUnavailable
+	}
+	info, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	stat, _, err := s.stats.CombinedCounter(statsName(id))
+	if err != nil {
+		return fmt.Errorf("read acl %d counters: %w", id, err)
+	}
+
+	s.baselineMu.Lock()
+	defer s.baselineMu.Unlock()
+	s.baselines[id] = reduce(stat, len(info.Rules))
+	return nil
+}
+
+// sinceReset subtracts the baseline recorded by the last reset, a baseline
+// that no longer fits the counters belongs to a replaced ACL and is dropped.
+// The caller holds baselineMu.
+func (s *Service) sinceReset(id domain.AclID, rules []domain.ACLRuleCounter) []domain.ACLRuleCounter {
+	baseline, ok := s.baselines[id]
+	if !ok {
+		return rules
+	}
+	if len(baseline) != len(rules) {
+		delete(s.baselines, id)
+		return rules
+	}
+	for i, r := range rules {
+		if r.Packets < baseline[i].Packets || r.Bytes < baseline[i].Bytes {
+			delete(s.baselines, id)
+			return rules
+		}
+	}
+	out := make([]domain.ACLRuleCounter, len(rules))
+	for i, r := range rules {
+		out[i] = domain.ACLRuleCounter{
+			Packets: r.Packets - baseline[i].Packets,
+			Bytes:   r.Bytes - baseline[i].Bytes,
+		}
+	}
+	return out
+}
+
+func (s *Service) dropBaseline(id domain.AclID) {
+	s.baselineMu.Lock()
+	defer s.baselineMu.Unlock()
+	delete(s.baselines, id)
+}
+
+// reduce sums the per-thread counters of the first n rules, the vectors of
+// idle threads may be shorter than the rule count
+func reduce(stat adapter.CombinedCounterStat, n int) []domain.ACLRuleCounter {
+	rules := make([]domain.ACLRuleCounter, n)
+	for _, thread := range stat {
+		for i := 0; i < n && i < len(thread); i++ {
+			rules[i].Packets += thread[i].Packets()
+			rules[i].Bytes += thread[i].Bytes()
+		}
+	}
+	return rules
+}
+
+func statsName(id domain.AclID) string {
+	return fmt.Sprintf("/acl/%d/matches", id)
+}
@@ -0,0 +1,490 @@
+package aclgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	aclServ "github.com/NikolayStepanov/RapidVPP/internal/service/vpp/acl"
+)
+
+var (
+	ErrNotFound = errors.New("acl object group not found")
+	ErrInUse    = errors.New("acl object group is referenced by acls")
+	ErrInvalid  = errors.New("invalid acl object group")
+	ErrChanged  = errors.New("acl was changed outside the controller")
+)
+
+// maxExpandedRules caps the rules the group references of an ACL may expand
+// into, rules without references are not counted
+const maxExpandedRules = 1024
+
+// ACLWriter pushes expanded rules to VPP
+type ACLWriter interface {
+	Create(ctx context.Context, name string, rules []domain.ACLRule) (domain.AclID, error)
+	Update(ctx context.Context, id domain.AclID, rules []domain.ACLRule) error
+	Delete(ctx context.Context, id domain.AclID) error
+	Get(ctx context.Context, id domain.AclID) (domain.ACLInfo, error)
+}
+
+// Service keeps address and port groups and the rules of ACLs referencing
+// them, VPP only ever sees the expanded rules. Groups and templates live in
+// memory only, after a restart ACLs keep their last expansion.
+type Service struct {
+	acls ACLWriter
+
+	mu            sync.Mutex
+	addressGroups map[string]domain.ACLAddressGroup
+	portGroups    map[string]domain.ACLPortGroup
+	// templates holds the rules of ACLs with group references
+	templates map[domain.AclID][]domain.ACLGroupRule
+	// pushed holds the expansion last written for each template, a group
+	// update refuses to overwrite an ACL whose rules in VPP differ from it
+	pushed map[domain.AclID][]domain.ACLRule
+}
+
+func NewService(acls ACLWriter) *Service {
+	return &Service{
+		acls:          acls,
+		addressGroups: make(map[string]domain.ACLAddressGroup),
+		portGroups:    make(map[string]domain.ACLPortGroup),
+		templates:     make(map[domain.AclID][]domain.ACLGroupRule),
+		pushed:        make(map[domain.AclID][]domain.ACLRule),
+	}
+}
+
+// CreateACL expands the group references and creates the ACL
+func (s *Service) CreateACL(ctx context.Context, name string, rules []domain.ACLGroupRule) (domain.AclID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expanded, err := s.expand(rules)
+	if err != nil {
+		return 0, err
+	}
+	id, err := s.acls.Create(ctx, name, expanded)
+	if err != nil {
+		return 0, err
+	}
+	s.setTemplate(id, rules, expanded)
+	return id, nil
+}
+
+// UpdateACL expands the group references and replaces the rules of the ACL
+func (s *Service) UpdateACL(ctx context.Context, id domain.AclID, rules []domain.ACLGroupRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expanded, err := s.expand(rules)
+	if err != nil {
+		return err
+	}
+	if err := s.acls.Update(ctx, id, expanded); err != nil {
+		return err
+	}
+	s.setTemplate(id, rules, expanded)
+	return nil
+}
+
+func (s *Service) DeleteACL(ctx context.Context, id domain.AclID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.acls.Delete(ctx, id); err != nil {
+		return err
+	}
+	delete(s.templates, id)
+	delete(s.pushed, id)
+	return nil
+}
+
+// SetAddressGroup creates or replaces an address group and updates the ACLs
+// referencing it, returning their indexes
+func (s *Service) SetAddressGroup(ctx context.Context, group domain.ACLAddressGroup) ([]domain.AclID, error) {
+	if err := validateAddressGroup(group); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, existed := s.addressGroups[group.Name]
+	s.addressGroups[group.Name] = group
+	return s.refresh(ctx, func(rule domain.ACLGroupRule) bool {
+		return rule.SrcGroup == group.Name || rule.DstGroup == group.Name
+	}, func() {
+		if existed {
+			s.addressGroups[group.Name] = old
+		} else {
+			delete(s.addressGroups, group.Name)
+		}
+	})
+}
+
+func (s *Service) DeleteAddressGroup(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.addressGroups[name]; !ok {
+		return fmt.Errorf("address group %s: %w", name, ErrNotFound)
+	}
+	if ids := s.referencing(func(rule domain.ACLGroupRule) bool {
+		return rule.SrcGroup == name || rule.DstGroup == name
+	}); len(ids) > 0 {
+		return fmt.Errorf("address group %s used by acls %v: %w", name, ids, ErrInUse)
+	}
+	delete(s.addressGroups, name)
+	return nil
+}
+
+func (s *Service) GetAddressGroup(name string) (domain.ACLAddressGroup, []domain.AclID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group, ok := s.addressGroups[name]
+	if !ok {
+		return domain.ACLAddressGroup{}, nil, fmt.Errorf("address group %s: %w", name, ErrNotFound)
+	}
+	return group, s.referencing(func(rule domain.ACLGroupRule) bool {
+		return rule.SrcGroup == name || rule.DstGroup == name
+	}), nil
+}
+
+func (s *Service) ListAddressGroups() []domain.ACLAddressGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := make([]domain.ACLAddressGroup, 0, len(s.addressGroups))
+	for _, group := range s.addressGroups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// SetPortGroup creates or replaces a port group and updates the ACLs
+// referencing it, returning their indexes
+func (s *Service) SetPortGroup(ctx context.Context, group domain.ACLPortGroup) ([]domain.AclID, error) {
+	if err := validatePortGroup(group); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, existed := s.portGroups[group.Name]
+	s.portGroups[group.Name] = group
+	return s.refresh(ctx, func(rule domain.ACLGroupRule) bool {
+		return rule.SrcPortGroup == group.Name || rule.DstPortGroup == group.Name
+	}, func() {
+		if existed {
+			s.portGroups[group.Name] = old
+		} else {
+			delete(s.portGroups, group.Name)
+		}
+	})
+}
+
+func (s *Service) DeletePortGroup(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.portGroups[name]; !ok {
+		return fmt.Errorf("port group %s: %w", name, ErrNotFound)
+	}
+	if ids := s.referencing(func(rule domain.ACLGroupRule) bool {
+		return rule.SrcPortGroup == name || rule.DstPortGroup == name
+	}); len(ids) > 0 {
+		return fmt.Errorf("port group %s used by acls %v: %w", name, ids, ErrInUse)
+	}
+	delete(s.portGroups, name)
+	return nil
+}
+
+func (s *Service) GetPortGroup(name string) (domain.ACLPortGroup, []domain.AclID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group, ok := s.portGroups[name]
+	if !ok {
+		return domain.ACLPortGroup{}, nil, fmt.Errorf("port group %s: %w", name, ErrNotFound)
+	}
+	return group, s.referencing(func(rule domain.ACLGroupRule) bool {
+		return rule.SrcPortGroup == name || rule.DstPortGroup == name
+	}), nil
+}
+
+func (s *Service) ListPortGroups() []domain.ACLPortGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := make([]domain.ACLPortGroup, 0, len(s.portGroups))
+	for _, group := range s.portGroups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// refresh re-expands and updates every ACL with a rule matching uses after
+// a group changed. All ACLs are expanded and checked against VPP before the
+// first update; on failure revert restores the previous group and the ACLs
+// already updated are pushed again with it.
+func (s *Service) refresh(ctx context.Context, uses func(domain.ACLGroupRule) bool, revert func()) ([]domain.AclID, error) {
+	ids := s.referencing(uses)
+	expanded := make([][]domain.ACLRule, len(ids))
+	for i, id := range ids {
+		rules, err := s.expand(s.templates[id])
+		if err == nil {
+			err = s.checkUnchanged(ctx, id)
+		}
+		if err != nil {
+			revert()
+			return nil, fmt.Errorf("acl %d: %w", id, err)
+		}
+		expanded[i] = rules
+	}
+
+	for i, id := range ids {
+		if err := s.acls.Update(ctx, id, expanded[i]); err != nil {
+			revert()
+			return nil, errors.Join(fmt.Errorf("update acl %d: %w", id, err), s.reapply(ctx, ids[:i]))
+		}
+		s.pushed[id] = expanded[i]
+	}
+	return ids, nil
+}
+
+// checkUnchanged fails with ErrChanged when the ACL was replaced or deleted
+// since the controller last wrote it, the template would overwrite it
+func (s *Service) checkUnchanged(ctx context.Context, id domain.AclID) error {
+	info, err := s.acls.Get(ctx, id)
+	if errors.Is(err, aclServ.ErrNotFound) {
+		return fmt.Errorf("%w: %w", ErrChanged, err)
+	}
+	if err != nil {
+		return err
+	}
+	if !slices.EqualFunc(info.Rules, s.pushed[id], sameRule) {
+		return ErrChanged
+	}
+	return nil
+}
+
+// reapply pushes the current expansion of the ACLs to VPP
+func (s *Service) reapply(ctx context.Context, ids []domain.AclID) error {
+	var errs []error
+	for _, id := range ids {
+		rules, err := s.expand(s.templates[id])
+		if err == nil {
+			err = s.acls.Update(ctx, id, rules)
+		}
+		if err == nil {
+			s.pushed[id] = rules
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restore acl %d: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// referencing returns the sorted indexes of ACLs with a rule matching uses
+func (s *Service) referencing(uses func(domain.ACLGroupRule) bool) []domain.AclID {
+	var ids []domain.AclID
+	for id, rules := range s.templates {
+		if slices.ContainsFunc(rules, uses) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (s *Service) setTemplate(id domain.AclID, rules []domain.ACLGroupRule, expanded []domain.ACLRule) {
+	if slices.ContainsFunc(rules, domain.ACLGroupRule.HasGroups) {
+		s.templates[id] = slices.Clone(rules)
+		s.pushed[id] = expanded
+		return
+	}
+	delete(s.templates, id)
+	delete(s.pushed, id)
+}
+
+// expand replaces group references by one rule per combination of group
+// members, an any address on the other side takes the family of the member.
+// Rules without references are passed on unchanged.
+func (s *Service) expand(rules []domain.ACLGroupRule) ([]domain.ACLRule, error) {
+	var out []domain.ACLRule
+	fromGroups := 0
+	for i, rule := range rules {
+		if !rule.HasGroups() {
+			out = append(out, rule.ACLRule)
+			continue
+		}
+		srcs, err := s.addresses(rule.Src, rule.SrcGroup)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		dsts, err := s.addresses(rule.Dst, rule.DstGroup)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		sports, err := s.ports(rule, rule.SrcPortLow, rule.SrcPortHigh, rule.SrcPortGroup)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		dports, err := s.ports(rule, rule.DstPortLow, rule.DstPortHigh, rule.DstPortGroup)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		for _, src := range srcs {
+			for _, dst := range dsts {
+				src, dst, ok := matchFamilies(src, dst)
+				if !ok {
+					continue
+				}
+				for _, sport := range sports {
+					for _, dport := range dports {
+						if fromGroups == maxExpandedRules {
+							return nil, fmt.Errorf("%w: group references expand to more than %d acl rules", ErrInvalid, maxExpandedRules)
+						}
+						fromGroups++
+						expanded := rule.ACLRule
+						expanded.Src, expanded.Dst = src, dst
+						expanded.SrcPortLow, expanded.SrcPortHigh = sport.Low, sport.High
+						expanded.DstPortLow, expanded.DstPortHigh = dport.Low, dport.High
+						out = append(out, expanded)
+					}
+				}
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: rules expand to an empty acl", ErrInvalid)
+	}
+	return out, nil
+}
+
+func (s *Service) addresses(prefix domain.IPWithPrefix, group string) ([]domain.IPWithPrefix, error) {
+	if group == "" {
+		return []domain.IPWithPrefix{prefix}, nil
+	}
+	g, ok := s.addressGroups[group]
+	if !ok {
+		return nil, fmt.Errorf("address group %s: %w", group, ErrNotFound)
+	}
+	return g.Prefixes, nil
+}
+
+func (s *Service) ports(rule domain.ACLGroupRule, low, high uint16, group string) ([]domain.PortRange, error) {
+	if group == "" {
+		return []domain.PortRange{{Low: low, High: high}}, nil
+	}
+	if rule.Proto != protoTCP && rule.Proto != protoUDP {
+		return nil, fmt.Errorf("%w: port group %s needs a tcp or udp rule", ErrInvalid, group)
+	}
+	g, ok := s.portGroups[group]
+	if !ok {
+		return nil, fmt.Errorf("port group %s: %w", group, ErrNotFound)
+	}
+	return g.Ports, nil
+}
+
+const (
+	protoTCP = 6
+	protoUDP = 17
+)
+
+// matchFamilies pairs a source and destination of the same family, an any
+// prefix is converted to the family of the other side
+func matchFamilies(src, dst domain.IPWithPrefix) (domain.IPWithPrefix, domain.IPWithPrefix, bool) {
+	srcV6, dstV6 := isIPv6(src), isIPv6(dst)
+	switch {
+	case srcV6 == dstV6:
+		return src, dst, true
+	case src.Prefix == 0:
+		src.Address = anyAddress(dstV6)
+		return src, dst, true
+	case dst.Prefix == 0:
+		dst.Address = anyAddress(srcV6)
+		return src, dst, true
+	}
+	return src, dst, false
+}
+
+// sameRule compares rules as VPP returns them, addresses are compared as
+// masked prefixes since their text form may differ
+func sameRule(a, b domain.ACLRule) bool {
+	if !samePrefix(a.Src, b.Src) || !samePrefix(a.Dst, b.Dst) {
+		return false
+	}
+	a.Src, a.Dst = b.Src, b.Dst
+	return a == b
+}
+
+func samePrefix(a, b domain.IPWithPrefix) bool {
+	pa, errA := netip.ParsePrefix(fmt.Sprintf("%s/%d", a.Address, a.Prefix))
+	pb, errB := netip.ParsePrefix(fmt.Sprintf("%s/%d", b.Address, b.Prefix))
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return pa.Masked() == pb.Masked()
+}
+
+func anyAddress(v6 bool) string {
+	if v6 {
+		return "::"
+	}
+	return "0.0.0.0"
+}
+
+func isIPv6(prefix domain.IPWithPrefix) bool {
+	return strings.Contains(prefix.Address, ":")
+}
+
+func validateAddressGroup(group domain.ACLAddressGroup) error {
+	if group.Name == "" {
+		return fmt.Errorf("%w: name is empty", ErrInvalid)
+	}
+	if len(group.Prefixes) == 0 {
+		return fmt.Errorf("%w: address group %s has no prefixes", ErrInvalid, group.Name)
+	}
+	for _, prefix := range group.Prefixes {
+		ip := net.ParseIP(prefix.Address)
+		if ip == nil {
+			return fmt.Errorf("%w: invalid address %q", ErrInvalid, prefix.Address)
+		}
+		bits := 128
+		if ip.To4() != nil {
+			bits = 32
+		}
+		if int(prefix.Prefix) > bits {
+			return fmt.Errorf("%w: invalid prefix length %s/%d", ErrInvalid, prefix.Address, prefix.Prefix)
+		}
+	}
+	return nil
+}
+
+func validatePortGroup(group domain.ACLPortGroup) error {
+	if group.Name == "" {
+		return fmt.Errorf("%w: name is empty", ErrInvalid)
+	}
+	if len(group.Ports) == 0 {
+		return fmt.Errorf("%w: port group %s has no ports", ErrInvalid, group.Name)
+	}
+	for _, r := range group.Ports {
+		if r.High < r.Low {
+			return fmt.Errorf("%w: reversed port range %d-%d", ErrInvalid, r.Low, r.High)
+		}
+	}
+	return nil
+}
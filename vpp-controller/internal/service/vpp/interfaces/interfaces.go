@@ -5,6 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
 
 	"github.com/NikolayStepanov/RapidVPP/internal/domain"
 	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
@@ -13,15 +17,26 @@ import (
 	"go.fd.io/govpp/binapi/acl"
 	interfaces "go.fd.io/govpp/binapi/interface"
 	"go.fd.io/govpp/binapi/interface_types"
+	"go.fd.io/govpp/binapi/ip"
 	"go.fd.io/govpp/binapi/ip_types"
+	"go.fd.io/govpp/binapi/urpf"
 	"go.uber.org/zap"
 )
 
 var (
 	ErrNotFound      = errors.New("interface not found")
 	ErrAlreadyExists = errors.New("resource already exists")
+	ErrACLNotFound   = errors.New("acl not found")
+	ErrInvalidACL    = errors.New("invalid acl list")
+	ErrInvalid       = errors.New("invalid argument")
 )
 
+// maxInterfaceACLs is the capacity of the u8 count of acl_interface_set_acl_list
+const maxInterfaceACLs = 255
+
+// MaxTagLength is the longest tag VPP stores, the 64 byte field is NUL terminated
+const MaxTagLength = 63
+
 const (
 	ACLDirOutput uint8 = 0 // tx
 	ACLDirInput  uint8 = 1 // rx
@@ -35,11 +50,156 @@ type (
 )
 
 type Service struct {
-	client *vpp.Client
+	client        *vpp.Client
+	metadataCache *MetadataCache
+	nameCache     *NameCache
+	// aclListMu serializes all updates of interface ACL lists, SetACLList and
+	// InsertACL read the list before writing it back
+	aclListMu sync.Mutex
+}
+
+// NameCache maps interface names to sw_if_index, it is refreshed
+// on cache misses and on VPP interface events
+type NameCache struct {
+	mu   sync.RWMutex
+	data map[string]uint32
+}
+
+// MetadataCache holds the controller-side metadata by sw_if_index, it lives in
+// memory only and is empty after a restart
+type MetadataCache struct {
+	mu   sync.RWMutex
+	data map[uint32]*domain.InterfaceMetadata
 }
 
 func NewService(client *vpp.Client) *Service {
-	return &Service{client: client}
+	return &Service{
+		client: client,
+		metadataCache: &MetadataCache{
+			data: make(map[uint32]*domain.InterfaceMetadata),
+		},
+		nameCache: &NameCache{
+			data: make(map[string]uint32),
+		},
+	}
+}
+
+func (s *Service) InitNameCache(ctx context.Context) error {
+	if err := s.refreshNameCache(ctx); err != nil {
+		return err
+	}
+
+	req := &interfaces.WantInterfaceEvents{
+		EnableDisable: 1,
+		PID:           uint32(os.Getpid()),
+	}
+	_, err := vpp.DoRequest[*interfaces.WantInterfaceEvents, *interfaces.WantInterfaceEventsReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("subscribe to interface events: %w", err)
+	}
+
+	watcher, err := s.client.WatchEvent(ctx, &interfaces.SwInterfaceEvent{})
+	if err != nil {
+		return err
+	}
+	go s.watchInterfaceEvents(ctx, watcher)
+
+	return nil
+}
+
+func (s *Service) watchInterfaceEvents(ctx context.Context, watcher api.Watcher) {
+	defer watcher.Close()
+
+	for msg := range watcher.Events() {
+		event, ok := msg.(*interfaces.SwInterfaceEvent)
+		if !ok {
+			continue
+		}
+		ifIndex := uint32(event.SwIfIndex)
+		if event.Deleted {
+			s.deleteNameCache(ifIndex)
+			s.deleteMetadataCache(ifIndex)
+			continue
+		}
+		if !s.hasNameCache(ifIndex) {
+			if err := s.refreshNameCache(ctx); err != nil {
+				logger.Warn("refresh interface name cache failed", zap.Error(err))
+			}
+		}
+	}
+
+	// indexes are not stable across a VPP restart, drop everything we know
+	s.nameCache.mu.Lock()
+	clear(s.nameCache.data)
+	s.nameCache.mu.Unlock()
+	logger.Warn("interface events watcher stopped, name cache cleared")
+}
+
+// ResolveInterface accepts either a numeric sw_if_index or an interface name
+func (s *Service) ResolveInterface(ctx context.Context, ref string) (uint32, error) {
+	if ref == "" {
+		return 0, fmt.Errorf("empty interface reference: %w", ErrInvalid)
+	}
+	if index, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		return uint32(index), nil
+	}
+
+	if index, ok := s.getNameCache(ref); ok {
+		return index, nil
+	}
+	if err := s.refreshNameCache(ctx); err != nil {
+		return 0, err
+	}
+	if index, ok := s.getNameCache(ref); ok {
+		return index, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrNotFound, ref)
+}
+
+func (s *Service) refreshNameCache(ctx context.Context) error {
+	list, err := s.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to dump interfaces: %w", err)
+	}
+
+	names := make(map[string]uint32, len(list))
+	for _, details := range list {
+		names[details.InterfaceName] = uint32(details.SwIfIndex)
+	}
+
+	s.nameCache.mu.Lock()
+	defer s.nameCache.mu.Unlock()
+	s.nameCache.data = names
+	return nil
+}
+
+func (s *Service) getNameCache(name string) (uint32, bool) {
+	s.nameCache.mu.RLock()
+	defer s.nameCache.mu.RUnlock()
+	index, ok := s.nameCache.data[name]
+	return index, ok
+}
+
+func (s *Service) hasNameCache(ifIndex uint32) bool {
+	s.nameCache.mu.RLock()
+	defer s.nameCache.mu.RUnlock()
+	for _, index := range s.nameCache.data {
+		if index == ifIndex {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) deleteNameCache(ifIndex uint32) {
+	s.nameCache.mu.Lock()
+	defer s.nameCache.mu.Unlock()
+	for name, index := range s.nameCache.data {
+		if index == ifIndex {
+			delete(s.nameCache.data, name)
+		}
+	}
 }
 
 func (s *Service) CreateLoopback(ctx context.Context) (interfaces.CreateLoopbackReply, error) {
@@ -49,6 +209,8 @@ func (s *Service) CreateLoopback(ctx context.Context) (interfaces.CreateLoopback
 	if err != nil {
 		return interfaces.CreateLoopbackReply{}, fmt.Errorf("create loopback operation failed: %w", err)
 	}
+	// VPP reuses the indexes of deleted interfaces
+	s.deleteMetadataCache(uint32(reply.SwIfIndex))
 
 	return *reply, nil
 }
@@ -98,6 +260,8 @@ func (s *Service) DeleteLoopback(ctx context.Context, ifIndex uint32) error {
 	if err != nil {
 		return fmt.Errorf("delete loopback failed: %w", err)
 	}
+	s.deleteMetadataCache(ifIndex)
+	s.deleteNameCache(ifIndex)
 	return nil
 }
 
@@ -119,6 +283,203 @@ func (s *Service) SetInterfaceIP(ctx context.Context, ifIndex uint32, IPPrefix d
 	return nil
 }
 
+func (s *Service) SetUnnumbered(ctx context.Context, ifIndex uint32, ipIfIndex uint32) error {
+	if ifIndex == ipIfIndex {
+		return fmt.Errorf("interface %d cannot be unnumbered to itself: %w", ifIndex, ErrInvalid)
+	}
+
+	req := &interfaces.SwInterfaceSetUnnumbered{
+		SwIfIndex:           interface_types.InterfaceIndex(ipIfIndex),
+		UnnumberedSwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		IsAdd:               true,
+	}
+
+	_, err := vpp.DoRequest[*interfaces.SwInterfaceSetUnnumbered, *interfaces.SwInterfaceSetUnnumberedReply](s.client, ctx, req)
+	if err != nil {
+		logger.Error("set interface unnumbered failed", zap.Error(err))
+		return mapVppError(err)
+	}
+	return nil
+}
+
+func (s *Service) DeleteUnnumbered(ctx context.Context, ifIndex uint32) error {
+	unnumbered, err := s.listUnnumbered(ctx, ifIndex)
+	if err != nil {
+		return err
+	}
+	if len(unnumbered) == 0 {
+		return ErrNotFound
+	}
+
+	req := &interfaces.SwInterfaceSetUnnumbered{
+		SwIfIndex:           interface_types.InterfaceIndex(unnumbered[0].IPInterfaceID),
+		UnnumberedSwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		IsAdd:               false,
+	}
+
+	_, err = vpp.DoRequest[*interfaces.SwInterfaceSetUnnumbered, *interfaces.SwInterfaceSetUnnumberedReply](s.client, ctx, req)
+	if err != nil {
+		logger.Error("delete interface unnumbered failed", zap.Error(err))
+		return mapVppError(err)
+	}
+	return nil
+}
+
+func (s *Service) ListUnnumbered(ctx context.Context) ([]domain.UnnumberedInterface, error) {
+	return s.listUnnumbered(ctx, 0xFFFFFFFF)
+}
+
+func (s *Service) listUnnumbered(ctx context.Context, ifIndex uint32) ([]domain.UnnumberedInterface, error) {
+	req := &ip.IPUnnumberedDump{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+	}
+
+	converter := func(msg api.Message) (domain.UnnumberedInterface, bool) {
+		details, ok := msg.(*ip.IPUnnumberedDetails)
+		if !ok {
+			return domain.UnnumberedInterface{}, false
+		}
+		return domain.UnnumberedInterface{
+			InterfaceID:   uint32(details.SwIfIndex),
+			IPInterfaceID: uint32(details.IPSwIfIndex),
+		}, true
+	}
+
+	unnumbered, err := vpp.Dump(ctx, s.client, req, converter)
+	if err != nil {
+		return nil, mapVppError(err)
+	}
+	return unnumbered, nil
+}
+
+func (s *Service) SetTag(ctx context.Context, ifIndex uint32, tag string) error {
+	if len(tag) > MaxTagLength {
+		return fmt.Errorf("tag exceeds %d characters: %w", MaxTagLength, ErrInvalid)
+	}
+
+	req := &interfaces.SwInterfaceTagAddDel{
+		IsAdd:     tag != "",
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		Tag:       tag,
+	}
+
+	_, err := vpp.DoRequest[*interfaces.SwInterfaceTagAddDel, *interfaces.SwInterfaceTagAddDelReply](s.client, ctx, req)
+	if err != nil {
+		logger.Error("set interface tag failed", zap.Error(err))
+		return mapVppError(err)
+	}
+	return nil
+}
+
+func (s *Service) SetMetadata(ctx context.Context, ifIndex uint32, metadata domain.InterfaceMetadata) error {
+	if err := s.checkInterfaceExists(ctx, ifIndex); err != nil {
+		return err
+	}
+
+	s.metadataCache.mu.Lock()
+	defer s.metadataCache.mu.Unlock()
+	s.metadataCache.data[ifIndex] = &metadata
+	return nil
+}
+
+func (s *Service) GetMetadata(ifIndex uint32) (domain.InterfaceMetadata, bool) {
+	s.metadataCache.mu.RLock()
+	defer s.metadataCache.mu.RUnlock()
+	metadata, ok := s.metadataCache.data[ifIndex]
+	if !ok {
+		return domain.InterfaceMetadata{}, false
+	}
+	return *metadata, true
+}
+
+func (s *Service) DeleteMetadata(ifIndex uint32) error {
+	s.metadataCache.mu.Lock()
+	defer s.metadataCache.mu.Unlock()
+	if _, ok := s.metadataCache.data[ifIndex]; !ok {
+		return ErrNotFound
+	}
+	delete(s.metadataCache.data, ifIndex)
+	return nil
+}
+
+func (s *Service) deleteMetadataCache(ifIndex uint32) {
+	s.metadataCache.mu.Lock()
+	defer s.metadataCache.mu.Unlock()
+	delete(s.metadataCache.data, ifIndex)
+}
+
+func (s *Service) checkInterfaceExists(ctx context.Context, ifIndex uint32) error {
+	request := &interfaces.SwInterfaceDump{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+	}
+
+	converter := func(msg api.Message) (uint32, bool) {
+		if details, ok := msg.(*interfaces.SwInterfaceDetails); ok {
+			return uint32(details.SwIfIndex), true
+		}
+		return 0, false
+	}
+
+	found, err := vpp.Dump(ctx, s.client, request, converter)
+	if err != nil {
+		return mapVppError(err)
+	}
+	for _, index := range found {
+		if index == ifIndex {
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// SetURPF sets the uRPF check of one address family and direction of the interface
+func (s *Service) SetURPF(ctx context.Context, setting domain.URPF) error {
+	af := ip_types.ADDRESS_IP4
+	if setting.IPv6 {
+		af = ip_types.ADDRESS_IP6
+	}
+
+	req := &urpf.UrpfUpdateV2{
+		IsInput:   !setting.Egress,
+		Mode:      urpf.UrpfMode(setting.Mode),
+		Af:        af,
+		SwIfIndex: interface_types.InterfaceIndex(setting.InterfaceID),
+		TableID:   setting.TableID,
+	}
+	_, err := vpp.DoRequest[*urpf.UrpfUpdateV2, *urpf.UrpfUpdateV2Reply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("set urpf on interface %d: %w", setting.InterfaceID, mapVppError(err))
+	}
+	return nil
+}
+
+// ListURPF returns the enabled uRPF checks of the interface, 0xFFFFFFFF lists all interfaces
+func (s *Service) ListURPF(ctx context.Context, ifIndex uint32) ([]domain.URPF, error) {
+	req := &urpf.UrpfInterfaceDump{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+	}
+
+	converter := func(msg api.Message) (domain.URPF, bool) {
+		details, ok := msg.(*urpf.UrpfInterfaceDetails)
+		if !ok {
+			return domain.URPF{}, false
+		}
+		return domain.URPF{
+			InterfaceID: uint32(details.SwIfIndex),
+			IPv6:        details.Af == ip_types.ADDRESS_IP6,
+			Egress:      !details.IsInput,
+			Mode:        domain.URPFMode(details.Mode),
+			TableID:     details.TableID,
+		}, true
+	}
+
+	settings, err := vpp.Dump(ctx, s.client, req, converter)
+	if err != nil {
+		return nil, mapVppError(err)
+	}
+	return settings, nil
+}
+
 func mapVppError(err error) error {
 	switch {
 	case errors.Is(err, api.NO_SUCH_ENTRY), errors.Is(err, api.INVALID_SW_IF_INDEX):
@@ -135,6 +496,10 @@ func (s *Service) AttachACL(ctx context.Context, ifIndex uint32, aclID uint32, d
 		return err
 	}
 
+	// an add or delete racing with SetACLList or InsertACL would be overwritten
+	s.aclListMu.Lock()
+	defer s.aclListMu.Unlock()
+
 	req := &acl.ACLInterfaceAddDel{
 		IsAdd:     true,
 		IsInput:   isInput,
@@ -156,6 +521,10 @@ func (s *Service) DetachACL(ctx context.Context, ifIndex uint32, aclID uint32, d
 		return err
 	}
 
+	// an add or delete racing with SetACLList or InsertACL would be overwritten
+	s.aclListMu.Lock()
+	defer s.aclListMu.Unlock()
+
 	req := &acl.ACLInterfaceAddDel{
 		IsAdd:     false,
 		IsInput:   isInput,
@@ -195,9 +564,92 @@ func (s *Service) ListACL(ctx context.Context, ifIndex uint32) (domain.ACLInterf
 	if err != nil {
 		return domain.ACLInterfaceList{}, err
 	}
+	if len(results) == 0 {
+		return domain.ACLInterfaceList{InterfaceID: ifIndex, InputACLs: []uint32{}, OutputACLs: []uint32{}}, nil
+	}
 	return results[0], nil
 }
 
+// SetACLList replaces the input and output ACLs of the interface in one
+// request, ACLs are evaluated in the given order; the effective lists are
+// read back from VPP
+func (s *Service) SetACLList(ctx context.Context, ifIndex uint32, input, output []uint32) (domain.ACLInterfaceList, error) {
+	s.aclListMu.Lock()
+	defer s.aclListMu.Unlock()
+
+	if err := s.setACLList(ctx, ifIndex, input, output); err != nil {
+		return domain.ACLInterfaceList{}, err
+	}
+	return s.ListACL(ctx, ifIndex)
+}
+
+// InsertACL places the ACL at position of the direction list, moving it when
+// it is already applied; a position past the end appends
+func (s *Service) InsertACL(ctx context.Context, ifIndex uint32, aclID uint32, dir uint8, position int) (domain.ACLInterfaceList, error) {
+	isInput, err := aclDirToIsInput(dir)
+	if err != nil {
+		return domain.ACLInterfaceList{}, fmt.Errorf("%w: %w", ErrInvalidACL, err)
+	}
+	if position < 0 {
+		return domain.ACLInterfaceList{}, fmt.Errorf("%w: negative position %d", ErrInvalidACL, position)
+	}
+
+	s.aclListMu.Lock()
+	defer s.aclListMu.Unlock()
+
+	current, err := s.ListACL(ctx, ifIndex)
+	if err != nil {
+		return domain.ACLInterfaceList{}, err
+	}
+	input, output := slices.Clone(current.InputACLs), slices.Clone(current.OutputACLs)
+	list := &output
+	if isInput {
+		list = &input
+	}
+	*list = slices.DeleteFunc(*list, func(id uint32) bool { return id == aclID })
+	*list = slices.Insert(*list, min(position, len(*list)), aclID)
+
+	if err := s.setACLList(ctx, ifIndex, input, output); err != nil {
+		return domain.ACLInterfaceList{}, err
+	}
+	return s.ListACL(ctx, ifIndex)
+}
+
+func (s *Service) setACLList(ctx context.Context, ifIndex uint32, input, output []uint32) error {
+	if len(input)+len(output) > maxInterfaceACLs {
+		return fmt.Errorf("%w: %d acls exceed %d", ErrInvalidACL, len(input)+len(output), maxInterfaceACLs)
+	}
+	for name, list := range map[string][]uint32{"input": input, "output": output} {
+		seen := make(map[uint32]struct{}, len(list))
+		for _, id := range list {
+			if _, ok := seen[id]; ok {
+				return fmt.Errorf("%w: acl %d is listed twice in %s", ErrInvalidACL, id, name)
+			}
+			seen[id] = struct{}{}
+		}
+	}
+
+	acls := make([]uint32, 0, len(input)+len(output))
+	acls = append(acls, input...)
+	acls = append(acls, output...)
+	req := &acl.ACLInterfaceSetACLList{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		Count:     uint8(len(acls)),
+		NInput:    uint8(len(input)),
+		Acls:      acls,
+	}
+	_, err := vpp.DoRequest[*acl.ACLInterfaceSetACLList, *acl.ACLInterfaceSetACLListReply](s.client, ctx, req)
+	switch {
+	case errors.Is(err, api.NO_SUCH_ENTRY):
+		return fmt.Errorf("set acl list of interface %d: %w: %w", ifIndex, ErrACLNotFound, err)
+	case errors.Is(err, api.INVALID_SW_IF_INDEX):
+		return fmt.Errorf("set acl list of interface %d: %w: %w", ifIndex, ErrNotFound, err)
+	case err != nil:
+		return fmt.Errorf("set acl list of interface %d: %w", ifIndex, err)
+	}
+	return nil
+}
+
 func aclDirToIsInput(dir uint8) (bool, error) {
 	switch dir {
 	case ACLDirInput:
@@ -1,12 +1,14 @@
 package ip
 
 import (
+	"container/heap"
 	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/netip"
+	"slices"
 	"sort"
 	"sync"
 
@@ -26,9 +28,19 @@ var systemPrefixes = []netip.Prefix{
 	netip.MustParsePrefix("100::/64"),  // Discard-only
 }
 
+var (
+	ErrLeakNotFound = errors.New("vrf leak not found")
+	ErrVRFNotFound  = errors.New("vrf not found")
+)
+
+const maxResolutionDepth = 16
+
 type Service struct {
-	client   *vpp.Client
-	vrfCache *VRFCache
+	client    *vpp.Client
+	vrfCache  *VRFCache
+	leakCache *LeakCache
+	// routeMu serializes read-modify-write updates of route paths
+	routeMu sync.Mutex
 }
 
 type VRFCache struct {
@@ -36,12 +48,21 @@ type VRFCache struct {
 	data map[uint32]*domain.VRFEntry
 }
 
+type LeakCache struct {
+	mu     sync.RWMutex
+	nextID uint32
+	data   map[uint32]*domain.VRFLeak
+}
+
 func NewService(client *vpp.Client) *Service {
 	return &Service{
 		client: client,
 		vrfCache: &VRFCache{
 			data: make(map[uint32]*domain.VRFEntry),
 		},
+		leakCache: &LeakCache{
+			data: make(map[uint32]*domain.VRFLeak),
+		},
 	}
 
 }
@@ -105,7 +126,7 @@ func (s *Service) getEntryVRFCache(id uint32) (*domain.VRFEntry, error) {
 	defer s.vrfCache.mu.RUnlock()
 	entry, ok := s.vrfCache.data[id]
 	if !ok {
-		return nil, fmt.Errorf("VRF with ID %d not found in cache", id)
+		return nil, fmt.Errorf("VRF with ID %d not found in cache: %w", id, ErrVRFNotFound)
 	}
 	return entry, nil
 }
@@ -149,12 +170,12 @@ func IsIPv6SystemRoute(pfx ip_types.Prefix) bool {
 }
 
 func (s *Service) AddRoute(ctx context.Context, route *domain.Route) error {
-	paths, err := mapper.BuildFibPaths(route.NextHops)
+	paths, err := mapper.BuildFibPaths(route.NextHops, route.Dst.IsIPv6())
 	if err != nil {
 		return fmt.Errorf("build fib paths: %w", err)
 	}
 
-	req := buildRouteRequest(route, paths)
+	req := buildRouteRequest(route, paths, true, len(paths) > 1)
 
 	_, err = vpp.DoRequest[*ip.IPRouteAddDel, *ip.IPRouteAddDelReply](s.client, ctx, req)
 	if err != nil {
@@ -164,10 +185,10 @@ func (s *Service) AddRoute(ctx context.Context, route *domain.Route) error {
 	return nil
 }
 
-func buildRouteRequest(route *domain.Route, paths []fib_types.FibPath) *ip.IPRouteAddDel {
+func buildRouteRequest(route *domain.Route, paths []fib_types.FibPath, isAdd, isMultipath bool) *ip.IPRouteAddDel {
 	return &ip.IPRouteAddDel{
-		IsAdd:       true,
-		IsMultipath: len(paths) > 1,
+		IsAdd:       isAdd,
+		IsMultipath: isMultipath,
 		Route: ip.IPRoute{
 			TableID: route.VRF,
 			Prefix: ip_types.Prefix{
@@ -181,24 +202,12 @@ func buildRouteRequest(route *domain.Route, paths []fib_types.FibPath) *ip.IPRou
 }
 
 func (s *Service) DeleteRoute(ctx context.Context, route *domain.Route) error {
-	paths, err := mapper.BuildFibPaths(route.NextHops)
+	paths, err := mapper.BuildFibPaths(route.NextHops, route.Dst.IsIPv6())
 	if err != nil {
 		return fmt.Errorf("build fib paths: %w", err)
 	}
 
-	req := &ip.IPRouteAddDel{
-		IsAdd:       false,
-		IsMultipath: len(paths) > 1,
-		Route: ip.IPRoute{
-			TableID: route.VRF,
-			Prefix: ip_types.Prefix{
-				Address: ip_types.NewAddress(net.ParseIP(route.Dst.Address)),
-				Len:     route.Dst.Prefix,
-			},
-			NPaths: uint8(len(paths)),
-			Paths:  paths,
-		},
-	}
+	req := buildRouteRequest(route, paths, false, len(paths) > 1)
 
 	_, err = vpp.DoRequest[*ip.IPRouteAddDel, *ip.IPRouteAddDelReply](s.client, ctx, req)
 	if err != nil {
@@ -208,20 +217,109 @@ func (s *Service) DeleteRoute(ctx context.Context, route *domain.Route) error {
 	return nil
 }
 
+// ReplaceRoute atomically replaces all paths of the route, the route is
+// created when it does not exist
+func (s *Service) ReplaceRoute(ctx context.Context, route *domain.Route) error {
+	if len(route.NextHops) == 0 {
+		return fmt.Errorf("route must contain at least one next hop")
+	}
+
+	paths, err := mapper.BuildFibPaths(route.NextHops, route.Dst.IsIPv6())
+	if err != nil {
+		return fmt.Errorf("build fib paths: %w", err)
+	}
+
+	// a non-multipath add updates the entry with exactly the given path list
+	req := buildRouteRequest(route, paths, true, false)
+
+	_, err = vpp.DoRequest[*ip.IPRouteAddDel, *ip.IPRouteAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("replace route in VPP: %w", err)
+	}
+
+	return nil
+}
+
+// ModifyRoutePaths adds and removes individual paths of a route, the
+// resulting path list is pushed with a single replace
+func (s *Service) ModifyRoutePaths(ctx context.Context, dst domain.IPWithPrefix, vrf uint32, add, remove []domain.NextHop) (domain.Route, error) {
+	s.routeMu.Lock()
+	defer s.routeMu.Unlock()
+
+	current, err := s.GetRoute(ctx, dst, vrf)
+	if err != nil {
+		if !errors.Is(err, api.NO_SUCH_ENTRY) {
+			return domain.Route{}, err
+		}
+		current = domain.Route{Dst: dst, VRF: vrf}
+	}
+
+	nextHops := make([]domain.NextHop, 0, len(current.NextHops)+len(add))
+	for _, nh := range current.NextHops {
+		if !slices.ContainsFunc(remove, nh.SamePath) {
+			nextHops = append(nextHops, nh)
+		}
+	}
+	for _, nh := range add {
+		if !slices.ContainsFunc(nextHops, nh.SamePath) {
+			nextHops = append(nextHops, nh)
+		}
+	}
+
+	route := domain.Route{Dst: dst, VRF: vrf, NextHops: nextHops}
+	if len(nextHops) == 0 {
+		if err := s.DeleteRoutePrefix(ctx, dst, vrf); err != nil {
+			return domain.Route{}, err
+		}
+		return route, nil
+	}
+
+	if err := s.ReplaceRoute(ctx, &route); err != nil {
+		return domain.Route{}, err
+	}
+
+	return route, nil
+}
+
+// DeleteRoutePrefix removes the route with all of its paths
+func (s *Service) DeleteRoutePrefix(ctx context.Context, dst domain.IPWithPrefix, vrf uint32) error {
+	route := &domain.Route{Dst: dst, VRF: vrf}
+	req := buildRouteRequest(route, nil, false, false)
+
+	_, err := vpp.DoRequest[*ip.IPRouteAddDel, *ip.IPRouteAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("delete route prefix from VPP: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) ListRoutes(ctx context.Context, vrf uint32) ([]domain.Route, error) {
-	request := &ip.IPRouteDump{
-		Table: ip.IPTable{
-			TableID: vrf,
-			IsIP6:   false,
-			Name:    "",
-		},
+	filter := domain.RouteFilter{IPv4: true, IPv6: true, IncludeSystem: true}
+
+	var routes []domain.Route
+	err := s.WalkRoutes(ctx, vrf, filter, func(route domain.Route) error {
+		routes = append(routes, route)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return routes, nil
+}
+
+// WalkRoutes streams the routes of vrf matching filter to fn in dump order,
+// an error returned by fn stops the walk
+func (s *Service) WalkRoutes(ctx context.Context, vrf uint32, filter domain.RouteFilter, fn func(domain.Route) error) error {
 	converter := func(msg api.Message) (domain.Route, bool) {
 		details, ok := msg.(*ip.IPRouteDetails)
 		if !ok {
 			return domain.Route{}, false
 		}
+		if !filter.IncludeSystem && IsSystemRoute(details) {
+			return domain.Route{}, false
+		}
 
 		route, err := mapper.ConvertRouteDetails(details)
 		if err != nil {
@@ -229,23 +327,110 @@ func (s *Service) ListRoutes(ctx context.Context, vrf uint32) ([]domain.Route, e
 			return domain.Route{}, false
 		}
 
-		return route, true
+		return route, filter.Match(route)
 	}
 
-	routesIPv4, err := vpp.Dump(ctx, s.client, request, converter)
-	if err != nil {
-		return nil, fmt.Errorf("failed to dump IPv4 routes: %w", err)
+	if filter.IPv4 {
+		if err := vpp.Walk(ctx, s.client, routeDumpRequest(vrf, false), converter, fn); err != nil {
+			return fmt.Errorf("failed to dump IPv4 routes: %w", err)
+		}
+	}
+	if filter.IPv6 {
+		if err := vpp.Walk(ctx, s.client, routeDumpRequest(vrf, true), converter, fn); err != nil {
+			return fmt.Errorf("failed to dump IPv6 routes: %w", err)
+		}
 	}
 
-	request.Table.IsIP6 = true
-	routesIPv6, err := vpp.Dump(ctx, s.client, request, converter)
+	return nil
+}
+
+// ListRoutesPage returns up to limit routes of vrf matching filter that
+// follow after, ordered by address family and prefix; more reports whether
+// further routes exist. VPP cannot start a dump at a prefix, so every page
+// walks the whole table of the cursor's family and later ones, keeping only
+// limit routes in memory. Reading a table of N routes costs N/limit dumps.
+func (s *Service) ListRoutesPage(ctx context.Context, vrf uint32, filter domain.RouteFilter, after netip.Prefix, limit int) ([]domain.Route, bool, error) {
+	if limit <= 0 {
+		return nil, false, fmt.Errorf("invalid page limit: %d", limit)
+	}
+	if after.IsValid() && after.Addr().Is6() {
+		// IPv4 prefixes all sort before the cursor
+		filter.IPv4 = false
+	}
+
+	// max-heap holding the limit+1 smallest keys seen so far
+	page := &routeHeap{}
+	err := s.WalkRoutes(ctx, vrf, filter, func(route domain.Route) error {
+		key, err := route.Dst.NetipPrefix()
+		if err != nil {
+			return nil
+		}
+		if after.IsValid() && comparePrefixes(key, after) <= 0 {
+			return nil
+		}
+		if page.Len() <= limit {
+			heap.Push(page, routeEntry{key: key, route: route})
+			return nil
+		}
+		if comparePrefixes(key, (*page)[0].key) < 0 {
+			(*page)[0] = routeEntry{key: key, route: route}
+			heap.Fix(page, 0)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to dump IPv6 routes: %w", err)
+		return nil, false, err
+	}
+
+	more := page.Len() > limit
+	if more {
+		heap.Pop(page)
+	}
+	routes := make([]domain.Route, page.Len())
+	for i := len(routes) - 1; i >= 0; i-- {
+		routes[i] = heap.Pop(page).(routeEntry).route
+	}
+
+	return routes, more, nil
+}
+
+func routeDumpRequest(vrf uint32, isIPv6 bool) *ip.IPRouteDump {
+	return &ip.IPRouteDump{
+		Table: ip.IPTable{
+			TableID: vrf,
+			IsIP6:   isIPv6,
+		},
 	}
+}
 
-	allRoutes := append(routesIPv4, routesIPv6...)
+// comparePrefixes orders IPv4 before IPv6, then by address and length
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
 
-	return allRoutes, nil
+type routeEntry struct {
+	key   netip.Prefix
+	route domain.Route
+}
+
+// routeHeap is a max-heap of routes by prefix
+type routeHeap []routeEntry
+
+func (h routeHeap) Len() int           { return len(h) }
+func (h routeHeap) Less(i, j int) bool { return comparePrefixes(h[i].key, h[j].key) > 0 }
+func (h routeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *routeHeap) Push(x any) { *h = append(*h, x.(routeEntry)) }
+
+func (h *routeHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
 }
 
 func (s *Service) GetRoute(ctx context.Context, dst domain.IPWithPrefix, vrf uint32) (domain.Route, error) {
@@ -277,6 +462,102 @@ func (s *Service) GetRoute(ctx context.Context, dst domain.IPWithPrefix, vrf uin
 	return route, nil
 }
 
+// LookupRoute returns the longest-prefix-match route for addr in vrf,
+// with recursive set the next hops are resolved until an interface is reached
+func (s *Service) LookupRoute(ctx context.Context, addr net.IP, vrf uint32, recursive bool) (domain.RouteLookup, error) {
+	route, err := s.lookupRoute(ctx, addr, vrf)
+	if err != nil {
+		return domain.RouteLookup{}, err
+	}
+
+	lookup := domain.RouteLookup{
+		Address: addr,
+		VRF:     vrf,
+		Route:   route,
+	}
+	if recursive {
+		lookup.Chain, err = s.resolveChain(ctx, addr, route)
+		if err != nil {
+			return domain.RouteLookup{}, err
+		}
+	}
+
+	return lookup, nil
+}
+
+func (s *Service) lookupRoute(ctx context.Context, addr net.IP, vrf uint32) (domain.Route, error) {
+	prefixLen := uint8(128)
+	if addr.To4() != nil {
+		prefixLen = 32
+	}
+
+	req := &ip.IPRouteLookup{
+		TableID: vrf,
+		Prefix: ip_types.Prefix{
+			Address: ip_types.NewAddress(addr),
+			Len:     prefixLen,
+		},
+		Exact: 0,
+	}
+
+	reply, err := vpp.DoRequest[*ip.IPRouteLookup, *ip.IPRouteLookupReply](s.client, ctx, req)
+	if err != nil {
+		return domain.Route{}, fmt.Errorf("IPRouteLookup failed: %w", err)
+	}
+
+	route, err := mapper.ConvertRouteDetails(&ip.IPRouteDetails{Route: reply.Route})
+	if err != nil {
+		return domain.Route{}, fmt.Errorf("failed to convert route details: %w", err)
+	}
+
+	return route, nil
+}
+
+func (s *Service) resolveChain(ctx context.Context, addr net.IP, route domain.Route) ([]domain.RouteResolution, error) {
+	var chain []domain.RouteResolution
+	visited := make(map[string]bool)
+	queue := nextResolutionSteps(addr, route)
+
+	for len(queue) > 0 && len(chain) < maxResolutionDepth {
+		current := queue[0]
+		queue = queue[1:]
+
+		key := fmt.Sprintf("%d/%s", current.VRF, current.Address)
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		resolved, err := s.lookupRoute(ctx, current.Address, current.VRF)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s in VRF %d: %w", current.Address, current.VRF, err)
+		}
+		current.Route = resolved
+		chain = append(chain, current)
+		queue = append(queue, nextResolutionSteps(current.Address, resolved)...)
+	}
+
+	return chain, nil
+}
+
+// nextResolutionSteps returns the lookups needed to resolve the paths of
+// route: recursive next hops without an interface and lookups in other tables
+func nextResolutionSteps(addr net.IP, route domain.Route) []domain.RouteResolution {
+	var steps []domain.RouteResolution
+	for _, nh := range route.NextHops {
+		switch nh.Type {
+		case domain.NextHopNormal:
+			if nh.IP != nil && nh.IfIndex == 0xFFFFFFFF {
+				steps = append(steps, domain.RouteResolution{Address: nh.IP, VRF: route.VRF})
+			}
+		case domain.NextHopLookup:
+			steps = append(steps, domain.RouteResolution{Address: addr, VRF: nh.NextVRF})
+		default:
+		}
+	}
+	return steps
+}
+
 func (s *Service) CreateVRF(ctx context.Context, id uint32, name string) error {
 	ipv4Req := &ip.IPTableAddDel{
 		IsAdd: true,
@@ -335,6 +616,8 @@ func (s *Service) enrichVRFWithNames(vrfMap map[uint32]*domain.VRF) {
 	for id, vrf := range vrfMap {
 		if entry, exists := s.vrfCache.data[id]; exists {
 			vrf.Name = entry.Name
+			vrf.FlowHashIPv4 = entry.FlowHashIPv4
+			vrf.FlowHashIPv6 = entry.FlowHashIPv6
 		}
 	}
 }
@@ -342,9 +625,60 @@ func (s *Service) addOrUpdateVRFCache(id uint32, name string) {
 	s.vrfCache.mu.Lock()
 	defer s.vrfCache.mu.Unlock()
 
-	s.vrfCache.data[id] = &domain.VRFEntry{
-		Name: name,
+	if entry, exists := s.vrfCache.data[id]; exists {
+		entry.Name = name
+		return
 	}
+	s.vrfCache.data[id] = &domain.VRFEntry{Name: name}
+}
+
+// SetFlowHash sets the ECMP flow hash of one address family of the VRF,
+// VPP has no call to read it back so the applied value is kept in the cache
+func (s *Service) SetFlowHash(ctx context.Context, vrf uint32, isIPv6 bool, flowHash domain.FlowHash) error {
+	if _, err := s.getEntryVRFCache(vrf); err != nil {
+		return err
+	}
+
+	req := &ip.SetIPFlowHashV2{
+		TableID:        vrf,
+		Af:             mapper.AddressFamily(isIPv6),
+		FlowHashConfig: mapper.BuildFlowHashConfig(flowHash),
+	}
+	_, err := vpp.DoRequest[*ip.SetIPFlowHashV2, *ip.SetIPFlowHashV2Reply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("set flow hash of VRF %d: %w", vrf, err)
+	}
+
+	s.vrfCache.mu.Lock()
+	defer s.vrfCache.mu.Unlock()
+	if entry, exists := s.vrfCache.data[vrf]; exists {
+		if isIPv6 {
+			entry.FlowHashIPv6 = &flowHash
+		} else {
+			entry.FlowHashIPv4 = &flowHash
+		}
+	}
+
+	return nil
+}
+
+// GetFlowHash returns the IPv4 and IPv6 flow hash of the VRF, nil for an
+// address family whose hash was not set since the controller started
+func (s *Service) GetFlowHash(vrf uint32) (*domain.FlowHash, *domain.FlowHash, error) {
+	entry, err := s.getEntryVRFCache(vrf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.vrfCache.mu.RLock()
+	defer s.vrfCache.mu.RUnlock()
+	return entry.FlowHashIPv4, entry.FlowHashIPv6, nil
+}
+
+// VRFExists reports whether the VRF was created via CreateVRF or found at startup
+func (s *Service) VRFExists(id uint32) bool {
+	_, err := s.getEntryVRFCache(id)
+	return err == nil
 }
 
 func (s *Service) DeleteVRF(ctx context.Context, id uint32) error {
@@ -458,3 +792,99 @@ func (s *Service) collectIPRoutes(ctx context.Context, vrfMap map[uint32]*domain
 	_, err := vpp.Dump(ctx, s.client, request, converter)
 	return err
 }
+
+func (s *Service) CreateVRFLeak(ctx context.Context, leak domain.VRFLeak) (uint32, error) {
+	if leak.TenantVRF == leak.SharedVRF {
+		return 0, fmt.Errorf("tenant and shared VRF must differ")
+	}
+	if len(leak.Import) == 0 && len(leak.Export) == 0 {
+		return 0, fmt.Errorf("leak must contain at least one import or export prefix")
+	}
+	if _, err := s.getEntryVRFCache(leak.TenantVRF); err != nil {
+		return 0, err
+	}
+	if _, err := s.getEntryVRFCache(leak.SharedVRF); err != nil {
+		return 0, err
+	}
+
+	routes := leak.Routes()
+	for i := range routes {
+		if err := s.addDelLeakPath(ctx, &routes[i], true); err != nil {
+			return 0, errors.Join(fmt.Errorf("install leak route %s/%d in VRF %d: %w",
+				routes[i].Dst.Address, routes[i].Dst.Prefix, routes[i].VRF, err),
+				s.deleteLeakPaths(ctx, routes[:i]))
+		}
+	}
+
+	s.leakCache.mu.Lock()
+	defer s.leakCache.mu.Unlock()
+	leak.ID = s.leakCache.nextID
+	s.leakCache.nextID++
+	s.leakCache.data[leak.ID] = &leak
+
+	return leak.ID, nil
+}
+
+func (s *Service) ListVRFLeaks(vrf uint32) []domain.VRFLeak {
+	s.leakCache.mu.RLock()
+	defer s.leakCache.mu.RUnlock()
+
+	leaks := make([]domain.VRFLeak, 0)
+	for _, leak := range s.leakCache.data {
+		if leak.TenantVRF == vrf || leak.SharedVRF == vrf {
+			leaks = append(leaks, *leak)
+		}
+	}
+
+	sort.Slice(leaks, func(i, j int) bool {
+		return leaks[i].ID < leaks[j].ID
+	})
+
+	return leaks
+}
+
+func (s *Service) DeleteVRFLeak(ctx context.Context, vrf uint32, id uint32) error {
+	s.leakCache.mu.Lock()
+	defer s.leakCache.mu.Unlock()
+	leak, ok := s.leakCache.data[id]
+	if !ok || (leak.TenantVRF != vrf && leak.SharedVRF != vrf) {
+		return ErrLeakNotFound
+	}
+
+	// the leak stays known until its routes are gone so a failed delete can be retried
+	if err := s.deleteLeakPaths(ctx, leak.Routes()); err != nil {
+		return fmt.Errorf("delete leak %d routes: %w", id, err)
+	}
+	delete(s.leakCache.data, id)
+
+	return nil
+}
+
+func (s *Service) deleteLeakPaths(ctx context.Context, routes []domain.Route) error {
+	var errs []error
+	for i := range routes {
+		if err := s.addDelLeakPath(ctx, &routes[i], false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// addDelLeakPath adds or removes the lookup path of a leak route as one path
+// of a multipath entry, so a route already installed for the same prefix keeps
+// its own paths and only the leak path is removed again
+func (s *Service) addDelLeakPath(ctx context.Context, route *domain.Route, isAdd bool) error {
+	paths, err := mapper.BuildFibPaths(route.NextHops, route.Dst.IsIPv6())
+	if err != nil {
+		return fmt.Errorf("build fib paths: %w", err)
+	}
+
+	req := buildRouteRequest(route, paths, isAdd, true)
+
+	_, err = vpp.DoRequest[*ip.IPRouteAddDel, *ip.IPRouteAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("update leak path in VPP: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package ip6nd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.fd.io/govpp/api"
+	"go.fd.io/govpp/binapi/interface_types"
+	"go.fd.io/govpp/binapi/ip"
+	"go.fd.io/govpp/binapi/ip6_nd"
+	"go.uber.org/zap"
+)
+
+type (
+	RAConfigReq   = ip6_nd.SwInterfaceIP6ndRaConfig
+	RAConfigReply = ip6_nd.SwInterfaceIP6ndRaConfigReply
+	RAPrefixReq   = ip6_nd.SwInterfaceIP6ndRaPrefix
+	RAPrefixReply = ip6_nd.SwInterfaceIP6ndRaPrefixReply
+)
+
+type Service struct {
+	client *vpp.Client
+}
+
+func NewService(client *vpp.Client) *Service {
+	return &Service{client: client}
+}
+
+// EnableIPv6 enables IPv6 processing on an interface without a global address,
+// a link-local address is derived by VPP
+func (s *Service) EnableIPv6(ctx context.Context, ifIndex uint32, enable bool) error {
+	req := &ip.SwInterfaceIP6EnableDisable{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		Enable:    enable,
+	}
+
+	_, err := vpp.DoRequest[*ip.SwInterfaceIP6EnableDisable, *ip.SwInterfaceIP6EnableDisableReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("ip6 enable/disable (enable=%t) on interface %d failed: %w", enable, ifIndex, err)
+	}
+
+	return nil
+}
+
+// SetRAConfig applies the full RA state, VPP toggles each flag either on or
+// off per request (is_no), so enabled and disabled flags are sent separately
+func (s *Service) SetRAConfig(ctx context.Context, ifIndex uint32, config domain.RAConfig) error {
+	enable := &RAConfigReq{
+		SwIfIndex:       interface_types.InterfaceIndex(ifIndex),
+		Suppress:        boolToFlag(config.Suppress),
+		Managed:         boolToFlag(config.Managed),
+		Other:           boolToFlag(config.Other),
+		LlOption:        boolToFlag(config.LinkLayerAddress),
+		SendUnicast:     boolToFlag(config.SendUnicast),
+		Cease:           boolToFlag(config.Cease),
+		MaxInterval:     config.MaxInterval,
+		MinInterval:     config.MinInterval,
+		InitialCount:    config.InitialCount,
+		InitialInterval: config.InitialInterval,
+	}
+	if config.RouterLifetime != nil {
+		enable.DefaultRouter = 1
+		enable.Lifetime = *config.RouterLifetime
+	}
+
+	_, err := vpp.DoRequest[*RAConfigReq, *RAConfigReply](s.client, ctx, enable)
+	if err != nil {
+		return fmt.Errorf("set RA config on interface %d failed: %w", ifIndex, err)
+	}
+
+	disable := &RAConfigReq{
+		SwIfIndex:   interface_types.InterfaceIndex(ifIndex),
+		IsNo:        true,
+		Suppress:    boolToFlag(!config.Suppress),
+		Managed:     boolToFlag(!config.Managed),
+		Other:       boolToFlag(!config.Other),
+		LlOption:    boolToFlag(!config.LinkLayerAddress),
+		SendUnicast: boolToFlag(!config.SendUnicast),
+		Cease:       boolToFlag(!config.Cease),
+	}
+
+	_, err = vpp.DoRequest[*RAConfigReq, *RAConfigReply](s.client, ctx, disable)
+	if err != nil {
+		return fmt.Errorf("reset RA flags on interface %d failed: %w", ifIndex, err)
+	}
+
+	return nil
+}
+
+func (s *Service) AddRAPrefix(ctx context.Context, ifIndex uint32, prefix domain.RAPrefix) error {
+	return s.addDelRAPrefix(ctx, ifIndex, prefix, true)
+}
+
+func (s *Service) DeleteRAPrefix(ctx context.Context, ifIndex uint32, prefix domain.IPWithPrefix) error {
+	return s.addDelRAPrefix(ctx, ifIndex, domain.RAPrefix{Prefix: prefix}, false)
+}
+
+func (s *Service) addDelRAPrefix(ctx context.Context, ifIndex uint32, prefix domain.RAPrefix, isAdd bool) error {
+	vppPrefix, err := mapper.IPWithPrefixToTypes(prefix.Prefix)
+	if err != nil {
+		return fmt.Errorf("invalid RA prefix: %w", err)
+	}
+	if vppPrefix.Address.Af != mapper.AddressFamily(true) {
+		return fmt.Errorf("RA prefix %s is not IPv6", prefix.Prefix.Address)
+	}
+
+	req := &RAPrefixReq{
+		SwIfIndex:    interface_types.InterfaceIndex(ifIndex),
+		Prefix:       vppPrefix,
+		UseDefault:   prefix.UseDefault,
+		NoAdvertise:  prefix.NoAdvertise,
+		OffLink:      prefix.OffLink,
+		NoAutoconfig: prefix.NoAutoconfig,
+		NoOnlink:     prefix.NoOnlink,
+		IsNo:         !isAdd,
+		ValLifetime:  prefix.ValidLifetime,
+		PrefLifetime: prefix.PreferredLifetime,
+	}
+
+	_, err = vpp.DoRequest[*RAPrefixReq, *RAPrefixReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("RA prefix add/del (is_add=%t) on interface %d failed: %w", isAdd, ifIndex, err)
+	}
+
+	return nil
+}
+
+func (s *Service) ListRA(ctx context.Context, ifIndex uint32) ([]domain.RAInfo, error) {
+	req := &ip6_nd.SwInterfaceIP6ndRaDump{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+	}
+
+	converter := func(msg api.Message) (domain.RAInfo, bool) {
+		details, ok := msg.(*ip6_nd.SwInterfaceIP6ndRaDetails)
+		if !ok {
+			return domain.RAInfo{}, false
+		}
+		info, err := mapper.ConvertRADetails(details)
+		if err != nil {
+			logger.Warn("failed to convert RA details", zap.Error(err))
+			return domain.RAInfo{}, false
+		}
+		return info, true
+	}
+
+	return vpp.Dump(ctx, s.client, req, converter)
+}
+
+func boolToFlag(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
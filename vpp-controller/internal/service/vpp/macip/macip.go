@@ -0,0 +1,176 @@
+package macip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
+	"go.fd.io/govpp/api"
+	"go.fd.io/govpp/binapi/acl"
+	"go.fd.io/govpp/binapi/interface_types"
+)
+
+var (
+	ErrNotFound           = errors.New("macip acl not found")
+	ErrAssignmentNotFound = errors.New("no macip acl assigned to interface")
+)
+
+const invalidIndex = ^uint32(0)
+
+type Service struct {
+	client *vpp.Client
+}
+
+func NewService(client *vpp.Client) *Service {
+	return &Service{client: client}
+}
+
+func (s *Service) Create(ctx context.Context, name string, rules []domain.MACIPRule) (domain.AclID, error) {
+	return s.addReplace(ctx, invalidIndex, name, rules)
+}
+
+// Replace swaps the rules of the ACL in place, interfaces stay assigned
+func (s *Service) Replace(ctx context.Context, id domain.AclID, rules []domain.MACIPRule) error {
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = s.addReplace(ctx, uint32(id), current.Name, rules)
+	return err
+}
+
+func (s *Service) addReplace(ctx context.Context, index uint32, name string, rules []domain.MACIPRule) (domain.AclID, error) {
+	if len(rules) == 0 {
+		return 0, fmt.Errorf("macip acl must contain at least one rule")
+	}
+	vppRules, err := mapper.ConvertMACIPRules(rules)
+	if err != nil {
+		return 0, err
+	}
+
+	req := &acl.MacipACLAddReplace{
+		ACLIndex: index,
+		Tag:      name,
+		Count:    uint32(len(vppRules)),
+		R:        vppRules,
+	}
+	reply, err := vpp.DoRequest[*acl.MacipACLAddReplace, *acl.MacipACLAddReplaceReply](s.client, ctx, req)
+	if err != nil {
+		return 0, fmt.Errorf("macip acl add/replace failed: %w", err)
+	}
+	return domain.AclID(reply.ACLIndex), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id domain.AclID) error {
+	req := &acl.MacipACLDel{ACLIndex: uint32(id)}
+	_, err := vpp.DoRequest[*acl.MacipACLDel, *acl.MacipACLDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to delete macip acl %d: %w", id, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) List(ctx context.Context) ([]domain.MACIPACL, error) {
+	return s.dump(ctx, invalidIndex)
+}
+
+func (s *Service) Get(ctx context.Context, id domain.AclID) (domain.MACIPACL, error) {
+	acls, err := s.dump(ctx, uint32(id))
+	if err != nil {
+		return domain.MACIPACL{}, err
+	}
+	for _, info := range acls {
+		if info.ID == id {
+			return info, nil
+		}
+	}
+	return domain.MACIPACL{}, fmt.Errorf("macip acl %d: %w", id, ErrNotFound)
+}
+
+func (s *Service) dump(ctx context.Context, index uint32) ([]domain.MACIPACL, error) {
+	converter := func(msg api.Message) (domain.MACIPACL, bool) {
+		details, ok := msg.(*acl.MacipACLDetails)
+		if !ok {
+			return domain.MACIPACL{}, false
+		}
+		rules, err := mapper.ConvertVPPMACIPRules(details.R)
+		if err != nil {
+			return domain.MACIPACL{}, false
+		}
+		return domain.MACIPACL{
+			ID:    domain.AclID(details.ACLIndex),
+			Name:  details.Tag,
+			Rules: rules,
+		}, true
+	}
+
+	acls, err := vpp.Dump(ctx, s.client, &acl.MacipACLDump{ACLIndex: index}, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump macip acls: %w", err)
+	}
+	return acls, nil
+}
+
+// Assign applies the MACIP ACL to the interface, replacing the one already assigned
+func (s *Service) Assign(ctx context.Context, ifIndex uint32, id domain.AclID) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.interfaceAddDel(ctx, ifIndex, id, true)
+}
+
+func (s *Service) Unassign(ctx context.Context, ifIndex uint32) error {
+	assignments, err := s.ListAssignments(ctx)
+	if err != nil {
+		return err
+	}
+	for _, a := range assignments {
+		if a.InterfaceID == ifIndex {
+			return s.interfaceAddDel(ctx, ifIndex, a.ACLID, false)
+		}
+	}
+	return fmt.Errorf("interface %d: %w", ifIndex, ErrAssignmentNotFound)
+}
+
+func (s *Service) interfaceAddDel(ctx context.Context, ifIndex uint32, id domain.AclID, isAdd bool) error {
+	req := &acl.MacipACLInterfaceAddDel{
+		IsAdd:     isAdd,
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		ACLIndex:  uint32(id),
+	}
+	_, err := vpp.DoRequest[*acl.MacipACLInterfaceAddDel, *acl.MacipACLInterfaceAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("macip acl %d interface %d (is_add=%t): %w", id, ifIndex, isAdd, err)
+	}
+	return nil
+}
+
+func (s *Service) ListAssignments(ctx context.Context) ([]domain.MACIPAssignment, error) {
+	converter := func(msg api.Message) (domain.MACIPAssignment, bool) {
+		details, ok := msg.(*acl.MacipACLInterfaceListDetails)
+		if !ok || len(details.Acls) == 0 {
+			return domain.MACIPAssignment{}, false
+		}
+		return domain.MACIPAssignment{
+			InterfaceID: uint32(details.SwIfIndex),
+			ACLID:       domain.AclID(details.Acls[0]),
+		}, true
+	}
+
+	req := &acl.MacipACLInterfaceListDump{SwIfIndex: interface_types.InterfaceIndex(invalidIndex)}
+	assignments, err := vpp.Dump(ctx, s.client, req, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump macip acl interfaces: %w", err)
+	}
+	return assignments, nil
+}
+
+func mapVppError(err error) error {
+	if errors.Is(err, api.NO_SUCH_ENTRY) || errors.Is(err, api.INVALID_VALUE) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
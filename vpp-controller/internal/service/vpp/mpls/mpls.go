@@ -0,0 +1,174 @@
+package mpls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
+	"go.fd.io/govpp/api"
+	"go.fd.io/govpp/binapi/interface_types"
+	"go.fd.io/govpp/binapi/mpls"
+)
+
+var (
+	ErrNotFound = errors.New("mpls entry not found")
+	ErrInvalid  = errors.New("invalid mpls route")
+)
+
+type Service struct {
+	client *vpp.Client
+}
+
+func NewService(client *vpp.Client) *Service {
+	return &Service{client: client}
+}
+
+// EnableMPLS enables or disables MPLS forwarding on the interface,
+// MPLS table 0 must exist first
+func (s *Service) EnableMPLS(ctx context.Context, ifIndex uint32, enable bool) error {
+	req := &mpls.SwInterfaceSetMplsEnable{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		Enable:    enable,
+	}
+	_, err := vpp.DoRequest[*mpls.SwInterfaceSetMplsEnable, *mpls.SwInterfaceSetMplsEnableReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("set mpls (enable=%t) on interface %d: %w", enable, ifIndex, mapVppError(err))
+	}
+	return nil
+}
+
+// ListMPLSInterfaces returns the indexes of interfaces with MPLS enabled
+func (s *Service) ListMPLSInterfaces(ctx context.Context) ([]uint32, error) {
+	req := &mpls.MplsInterfaceDump{SwIfIndex: interface_types.InterfaceIndex(^uint32(0))}
+
+	converter := func(msg api.Message) (uint32, bool) {
+		details, ok := msg.(*mpls.MplsInterfaceDetails)
+		if !ok {
+			return 0, false
+		}
+		return uint32(details.SwIfIndex), true
+	}
+
+	ifIndexes, err := vpp.Dump(ctx, s.client, req, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump mpls interfaces: %w", err)
+	}
+	return ifIndexes, nil
+}
+
+func (s *Service) CreateMPLSTable(ctx context.Context, table domain.MPLSTable) error {
+	return s.addDelTable(ctx, table, true)
+}
+
+func (s *Service) DeleteMPLSTable(ctx context.Context, id uint32) error {
+	return s.addDelTable(ctx, domain.MPLSTable{ID: id}, false)
+}
+
+func (s *Service) addDelTable(ctx context.Context, table domain.MPLSTable, isAdd bool) error {
+	req := &mpls.MplsTableAddDel{
+		MtIsAdd: isAdd,
+		MtTable: mpls.MplsTable{
+			MtTableID: table.ID,
+			MtName:    table.Name,
+		},
+	}
+	_, err := vpp.DoRequest[*mpls.MplsTableAddDel, *mpls.MplsTableAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("mpls table add/del (is_add=%t) failed: %w", isAdd, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) ListMPLSTables(ctx context.Context) ([]domain.MPLSTable, error) {
+	converter := func(msg api.Message) (domain.MPLSTable, bool) {
+		details, ok := msg.(*mpls.MplsTableDetails)
+		if !ok {
+			return domain.MPLSTable{}, false
+		}
+		return domain.MPLSTable{
+			ID:   details.MtTable.MtTableID,
+			Name: details.MtTable.MtName,
+		}, true
+	}
+
+	tables, err := vpp.Dump(ctx, s.client, &mpls.MplsTableDump{}, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump mpls tables: %w", err)
+	}
+	return tables, nil
+}
+
+func (s *Service) AddMPLSRoute(ctx context.Context, route domain.MPLSRoute) error {
+	return s.addDelRoute(ctx, route, true)
+}
+
+// DeleteMPLSRoute removes the local label with all of its paths
+func (s *Service) DeleteMPLSRoute(ctx context.Context, table, label uint32, eos bool) error {
+	var mrEos uint8
+	if eos {
+		mrEos = 1
+	}
+	req := &mpls.MplsRouteAddDel{
+		MrIsAdd: false,
+		MrRoute: mpls.MplsRoute{
+			MrTableID: table,
+			MrLabel:   label,
+			MrEos:     mrEos,
+		},
+	}
+	_, err := vpp.DoRequest[*mpls.MplsRouteAddDel, *mpls.MplsRouteAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("delete mpls route %d: %w", label, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) addDelRoute(ctx context.Context, route domain.MPLSRoute, isAdd bool) error {
+	mplsRoute, err := mapper.BuildMplsRoute(route)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	// a non-multipath add replaces the paths of an existing label
+	req := &mpls.MplsRouteAddDel{
+		MrIsAdd:       isAdd,
+		MrIsMultipath: false,
+		MrRoute:       mplsRoute,
+	}
+	_, err = vpp.DoRequest[*mpls.MplsRouteAddDel, *mpls.MplsRouteAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("mpls route add/del (is_add=%t) failed: %w", isAdd, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) ListMPLSRoutes(ctx context.Context, table uint32) ([]domain.MPLSRoute, error) {
+	converter := func(msg api.Message) (domain.MPLSRoute, bool) {
+		details, ok := msg.(*mpls.MplsRouteDetails)
+		if !ok {
+			return domain.MPLSRoute{}, false
+		}
+		route, err := mapper.ConvertMplsRouteDetails(details)
+		if err != nil {
+			return domain.MPLSRoute{}, false
+		}
+		return route, true
+	}
+
+	req := &mpls.MplsRouteDump{Table: mpls.MplsTable{MtTableID: table}}
+	routes, err := vpp.Dump(ctx, s.client, req, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump mpls routes: %w", err)
+	}
+	return routes, nil
+}
+
+func mapVppError(err error) error {
+	if errors.Is(err, api.NO_SUCH_ENTRY) || errors.Is(err, api.NO_SUCH_FIB) || errors.Is(err, api.INVALID_SW_IF_INDEX) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
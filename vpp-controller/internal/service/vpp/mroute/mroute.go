@@ -0,0 +1,82 @@
+package mroute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
+	"go.fd.io/govpp/api"
+	"go.fd.io/govpp/binapi/ip"
+)
+
+var ErrNotFound = errors.New("multicast route not found")
+
+type Service struct {
+	client *vpp.Client
+}
+
+func NewService(client *vpp.Client) *Service {
+	return &Service{client: client}
+}
+
+// AddMRoute creates the entry or updates its flags, the given paths are
+// added to or updated on the existing ones
+func (s *Service) AddMRoute(ctx context.Context, route domain.MRoute) error {
+	return s.addDelMRoute(ctx, route, true)
+}
+
+// DeleteMRoute removes the given paths from the entry, without paths the
+// whole entry is deleted
+func (s *Service) DeleteMRoute(ctx context.Context, route domain.MRoute) error {
+	return s.addDelMRoute(ctx, route, false)
+}
+
+func (s *Service) addDelMRoute(ctx context.Context, route domain.MRoute, isAdd bool) error {
+	mroute, err := mapper.BuildMroute(route)
+	if err != nil {
+		return err
+	}
+
+	req := &ip.IPMrouteAddDel{
+		IsAdd:       isAdd,
+		IsMultipath: len(mroute.Paths) > 0,
+		Route:       mroute,
+	}
+	_, err = vpp.DoRequest[*ip.IPMrouteAddDel, *ip.IPMrouteAddDelReply](s.client, ctx, req)
+	if err != nil {
+		if errors.Is(err, api.NO_SUCH_ENTRY) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("mroute add/del (is_add=%t) failed: %w", isAdd, err)
+	}
+
+	return nil
+}
+
+// ListMRoutes dumps the IPv4 and IPv6 multicast tables of the VRF
+func (s *Service) ListMRoutes(ctx context.Context, vrf uint32) ([]domain.MRoute, error) {
+	converter := func(msg api.Message) (domain.MRoute, bool) {
+		details, ok := msg.(*ip.IPMrouteDetails)
+		if !ok {
+			return domain.MRoute{}, false
+		}
+		return mapper.ConvertMrouteDetails(details), true
+	}
+
+	req := &ip.IPMrouteDump{Table: ip.IPTable{TableID: vrf}}
+	routes, err := vpp.Dump(ctx, s.client, req, converter)
+	if err != nil {
+		return nil, fmt.Errorf("IPv4: %w", err)
+	}
+
+	req.Table.IsIP6 = true
+	ipv6, err := vpp.Dump(ctx, s.client, req, converter)
+	if err != nil {
+		return nil, fmt.Errorf("IPv6: %w", err)
+	}
+
+	return append(routes, ipv6...), nil
+}
@@ -0,0 +1,192 @@
+package neighbor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
+	"go.fd.io/govpp/api"
+	interfaces "go.fd.io/govpp/binapi/interface"
+	"go.fd.io/govpp/binapi/interface_types"
+	"go.fd.io/govpp/binapi/ip_neighbor"
+)
+
+var ErrNotFound = errors.New("neighbor not found")
+
+type Service struct {
+	client *vpp.Client
+}
+
+func NewService(client *vpp.Client) *Service {
+	return &Service{client: client}
+}
+
+func (s *Service) AddNeighbor(ctx context.Context, neighbor domain.Neighbor) error {
+	if neighbor.MAC == nil {
+		return fmt.Errorf("neighbor mac address is required")
+	}
+	return s.addDelNeighbor(ctx, neighbor, true)
+}
+
+func (s *Service) DeleteNeighbor(ctx context.Context, neighbor domain.Neighbor) error {
+	return s.addDelNeighbor(ctx, neighbor, false)
+}
+
+func (s *Service) addDelNeighbor(ctx context.Context, neighbor domain.Neighbor, isAdd bool) error {
+	vppNeighbor, err := mapper.ConvertNeighbor(neighbor)
+	if err != nil {
+		return err
+	}
+
+	req := &ip_neighbor.IPNeighborAddDel{
+		IsAdd:    isAdd,
+		Neighbor: vppNeighbor,
+	}
+
+	_, err = vpp.DoRequest[*ip_neighbor.IPNeighborAddDel, *ip_neighbor.IPNeighborAddDelReply](s.client, ctx, req)
+	if err != nil {
+		if errors.Is(err, api.NO_SUCH_ENTRY) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("neighbor add/del (is_add=%t) failed: %w", isAdd, err)
+	}
+
+	return nil
+}
+
+func (s *Service) ListNeighbors(ctx context.Context, filter domain.NeighborFilter) ([]domain.Neighbor, error) {
+	var neighbors []domain.Neighbor
+
+	if filter.IPv4 {
+		ipv4, err := s.dumpNeighbors(ctx, filter.InterfaceID, false)
+		if err != nil {
+			return nil, fmt.Errorf("IPv4: %w", err)
+		}
+		neighbors = append(neighbors, ipv4...)
+	}
+
+	if filter.IPv6 {
+		ipv6, err := s.dumpNeighbors(ctx, filter.InterfaceID, true)
+		if err != nil {
+			return nil, fmt.Errorf("IPv6: %w", err)
+		}
+		neighbors = append(neighbors, ipv6...)
+	}
+
+	if filter.VRF == nil {
+		return neighbors, nil
+	}
+
+	return s.filterByVRF(ctx, neighbors, *filter.VRF)
+}
+
+func (s *Service) dumpNeighbors(ctx context.Context, ifIndex uint32, isIPv6 bool) ([]domain.Neighbor, error) {
+	req := &ip_neighbor.IPNeighborDump{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		Af:        mapper.AddressFamily(isIPv6),
+	}
+
+	converter := func(msg api.Message) (domain.Neighbor, bool) {
+		details, ok := msg.(*ip_neighbor.IPNeighborDetails)
+		if !ok {
+			return domain.Neighbor{}, false
+		}
+		return mapper.ConvertNeighborDetails(details), true
+	}
+
+	return vpp.Dump(ctx, s.client, req, converter)
+}
+
+func (s *Service) filterByVRF(ctx context.Context, neighbors []domain.Neighbor, vrf uint32) ([]domain.Neighbor, error) {
+	type tableKey struct {
+		ifIndex uint32
+		isIPv6  bool
+	}
+	tables := make(map[tableKey]uint32)
+
+	result := make([]domain.Neighbor, 0, len(neighbors))
+	for _, neighbor := range neighbors {
+		key := tableKey{ifIndex: neighbor.InterfaceID, isIPv6: neighbor.IP.To4() == nil}
+
+		table, ok := tables[key]
+		if !ok {
+			var err error
+			table, err = s.getInterfaceTable(ctx, key.ifIndex, key.isIPv6)
+			if err != nil {
+				return nil, err
+			}
+			tables[key] = table
+		}
+
+		if table == vrf {
+			result = append(result, neighbor)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) getInterfaceTable(ctx context.Context, ifIndex uint32, isIPv6 bool) (uint32, error) {
+	req := &interfaces.SwInterfaceGetTable{
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+		IsIPv6:    isIPv6,
+	}
+
+	reply, err := vpp.DoRequest[*interfaces.SwInterfaceGetTable, *interfaces.SwInterfaceGetTableReply](s.client, ctx, req)
+	if err != nil {
+		return 0, fmt.Errorf("get table of interface %d failed: %w", ifIndex, err)
+	}
+
+	return reply.VrfID, nil
+}
+
+func (s *Service) FlushNeighbors(ctx context.Context, ifIndex uint32, isIPv6 bool) error {
+	req := &ip_neighbor.IPNeighborFlush{
+		Af:        mapper.AddressFamily(isIPv6),
+		SwIfIndex: interface_types.InterfaceIndex(ifIndex),
+	}
+
+	_, err := vpp.DoRequest[*ip_neighbor.IPNeighborFlush, *ip_neighbor.IPNeighborFlushReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("flush neighbors failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetNeighborConfig(ctx context.Context, isIPv6 bool) (domain.NeighborConfig, error) {
+	req := &ip_neighbor.IPNeighborConfigGet{
+		Af: mapper.AddressFamily(isIPv6),
+	}
+
+	reply, err := vpp.DoRequest[*ip_neighbor.IPNeighborConfigGet, *ip_neighbor.IPNeighborConfigGetReply](s.client, ctx, req)
+	if err != nil {
+		return domain.NeighborConfig{}, fmt.Errorf("get neighbor config failed: %w", err)
+	}
+
+	return domain.NeighborConfig{
+		IPv6:      isIPv6,
+		MaxNumber: reply.MaxNumber,
+		MaxAge:    reply.MaxAge,
+		Recycle:   reply.Recycle,
+	}, nil
+}
+
+func (s *Service) SetNeighborConfig(ctx context.Context, config domain.NeighborConfig) error {
+	req := &ip_neighbor.IPNeighborConfig{
+		Af:        mapper.AddressFamily(config.IPv6),
+		MaxNumber: config.MaxNumber,
+		MaxAge:    config.MaxAge,
+		Recycle:   config.Recycle,
+	}
+
+	_, err := vpp.DoRequest[*ip_neighbor.IPNeighborConfig, *ip_neighbor.IPNeighborConfigReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("set neighbor config failed: %w", err)
+	}
+
+	return nil
+}
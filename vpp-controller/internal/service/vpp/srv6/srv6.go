@@ -0,0 +1,375 @@
+package srv6
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/NikolayStepanov/RapidVPP/internal/domain"
+	"github.com/NikolayStepanov/RapidVPP/internal/infrastructure/vpp"
+	"github.com/NikolayStepanov/RapidVPP/internal/mapper"
+	"github.com/NikolayStepanov/RapidVPP/pkg/logger"
+	"go.fd.io/govpp/api"
+	"go.fd.io/govpp/binapi/interface_types"
+	"go.fd.io/govpp/binapi/ip_types"
+	"go.fd.io/govpp/binapi/sr"
+	"go.fd.io/govpp/binapi/sr_types"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotFound        = errors.New("srv6 entry not found")
+	ErrVRFNotFound     = errors.New("vrf not found")
+	ErrLastSegmentList = errors.New("policy must keep at least one segment list")
+	ErrInvalid         = errors.New("invalid srv6 configuration")
+)
+
+// defaultSteering are the prefixes installed when a whole VRF is steered
+var defaultSteering = []domain.IPWithPrefix{
+	{Address: "0.0.0.0", Prefix: 0},
+	{Address: "::", Prefix: 0},
+}
+
+// VRFChecker reports whether a VRF is known to the controller
+type VRFChecker interface {
+	VRFExists(id uint32) bool
+}
+
+type Service struct {
+	client *vpp.Client
+	vrfs   VRFChecker
+}
+
+func NewService(client *vpp.Client, vrfs VRFChecker) *Service {
+	return &Service{client: client, vrfs: vrfs}
+}
+
+// AddLocalSID instantiates a local SID, End.DT* SIDs must decapsulate into
+// a VRF created via CreateVRF
+func (s *Service) AddLocalSID(ctx context.Context, sid domain.SRv6LocalSID) error {
+	if !mapper.IsIPv6(sid.SID) {
+		return fmt.Errorf("%w: sid %v is not an IPv6 address", ErrInvalid, sid.SID)
+	}
+	if sid.Behavior.IsDecap() && !s.vrfs.VRFExists(sid.VRF) {
+		return fmt.Errorf("%v target vrf %d: %w", sid.Behavior, sid.VRF, ErrVRFNotFound)
+	}
+	if sid.Behavior == domain.SRv6EndDT46 {
+		return s.addDT46LocalSID(ctx, sid)
+	}
+	behavior, err := mapper.BuildSrBehavior(sid.Behavior)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	req := &sr.SrLocalsidAddDel{
+		IsDel:     false,
+		Localsid:  ip_types.NewIP6Address(sid.SID),
+		EndPsp:    sid.PSP,
+		Behavior:  behavior,
+		SwIfIndex: interface_types.InterfaceIndex(^uint32(0)),
+		FibTable:  sid.Table,
+	}
+	switch {
+	case sid.Behavior == domain.SRv6EndX:
+		if sid.NextHop == nil {
+			return fmt.Errorf("%w: End.X requires a next hop", ErrInvalid)
+		}
+		req.SwIfIndex = interface_types.InterfaceIndex(sid.InterfaceID)
+		req.NhAddr = ip_types.NewAddress(sid.NextHop)
+	case sid.Behavior.IsDecap():
+		// End.DT* carries the lookup table in sw_if_index
+		req.SwIfIndex = interface_types.InterfaceIndex(sid.VRF)
+	}
+
+	_, err = vpp.DoRequest[*sr.SrLocalsidAddDel, *sr.SrLocalsidAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("add local sid %v: %w", sid.SID, mapVppError(err))
+	}
+	return nil
+}
+
+// addDT46LocalSID installs an End.DT46 SID through the CLI, the sr_behavior
+// API enum has no value for it. The API delete removes it like any other SID.
+func (s *Service) addDT46LocalSID(ctx context.Context, sid domain.SRv6LocalSID) error {
+	cmd := fmt.Sprintf("sr localsid address %s fib-table %d behavior end.dt46 %d", sid.SID, sid.Table, sid.VRF)
+	output, err := vpp.RunCLI(ctx, s.client, cmd)
+	if err != nil {
+		return fmt.Errorf("add local sid %v: %w", sid.SID, err)
+	}
+	if output != "" {
+		// the CLI reports errors as text with a zero retval
+		return fmt.Errorf("%w: add local sid %v: %s", ErrInvalid, sid.SID, strings.TrimSpace(output))
+	}
+	return nil
+}
+
+func (s *Service) DeleteLocalSID(ctx context.Context, sid net.IP, table uint32) error {
+	req := &sr.SrLocalsidAddDel{
+		IsDel:     true,
+		Localsid:  ip_types.NewIP6Address(sid),
+		SwIfIndex: interface_types.InterfaceIndex(^uint32(0)),
+		FibTable:  table,
+	}
+	_, err := vpp.DoRequest[*sr.SrLocalsidAddDel, *sr.SrLocalsidAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("delete local sid %v: %w", sid, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) ListLocalSIDs(ctx context.Context) ([]domain.SRv6LocalSID, error) {
+	converter := func(msg api.Message) (domain.SRv6LocalSID, bool) {
+		details, ok := msg.(*sr.SrLocalsidsDetails)
+		if !ok {
+			return domain.SRv6LocalSID{}, false
+		}
+		return mapper.ConvertSrLocalsidDetails(details)
+	}
+
+	sids, err := vpp.Dump(ctx, s.client, &sr.SrLocalsidsDump{}, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump local sids: %w", err)
+	}
+	return sids, nil
+}
+
+// CreatePolicy adds the policy with its first segment list and appends the
+// others, the policy is removed again if any list is rejected
+func (s *Service) CreatePolicy(ctx context.Context, policy domain.SRv6Policy) error {
+	if !mapper.IsIPv6(policy.BSID) {
+		return fmt.Errorf("%w: bsid %v is not an IPv6 address", ErrInvalid, policy.BSID)
+	}
+	if len(policy.SegmentLists) == 0 {
+		return fmt.Errorf("%w: policy requires at least one segment list", ErrInvalid)
+	}
+	sidLists := make([]sr.Srv6SidList, 0, len(policy.SegmentLists))
+	for i, list := range policy.SegmentLists {
+		sidList, err := mapper.BuildSrv6SidList(list)
+		if err != nil {
+			return fmt.Errorf("%w: segment list %d: %w", ErrInvalid, i, err)
+		}
+		sidLists = append(sidLists, sidList)
+	}
+
+	req := &sr.SrPolicyAdd{
+		BsidAddr: ip_types.NewIP6Address(policy.BSID),
+		Weight:   sidLists[0].Weight,
+		IsEncap:  policy.Encap,
+		IsSpray:  policy.Spray,
+		FibTable: policy.Table,
+		Sids:     sidLists[0],
+	}
+	_, err := vpp.DoRequest[*sr.SrPolicyAdd, *sr.SrPolicyAddReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("add sr policy %v: %w", policy.BSID, mapVppError(err))
+	}
+
+	for i, sidList := range sidLists[1:] {
+		if err := s.addSidList(ctx, policy.BSID, policy.Table, sidList); err != nil {
+			if delErr := s.DeletePolicy(ctx, policy.BSID); delErr != nil {
+				logger.Error("failed to roll back sr policy",
+					zap.Stringer("bsid", policy.BSID), zap.Error(delErr))
+			}
+			return fmt.Errorf("segment list %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) DeletePolicy(ctx context.Context, bsid net.IP) error {
+	req := &sr.SrPolicyDel{
+		BsidAddr:      ip_types.NewIP6Address(bsid),
+		SrPolicyIndex: ^uint32(0),
+	}
+	_, err := vpp.DoRequest[*sr.SrPolicyDel, *sr.SrPolicyDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("delete sr policy %v: %w", bsid, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) ListPolicies(ctx context.Context) ([]domain.SRv6Policy, error) {
+	converter := func(msg api.Message) (domain.SRv6Policy, bool) {
+		details, ok := msg.(*sr.SrPoliciesWithSlIndexDetails)
+		if !ok {
+			return domain.SRv6Policy{}, false
+		}
+		return mapper.ConvertSrPolicyDetails(details), true
+	}
+
+	policies, err := vpp.Dump(ctx, s.client, &sr.SrPoliciesWithSlIndexDump{}, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump sr policies: %w", err)
+	}
+	return policies, nil
+}
+
+func (s *Service) GetPolicy(ctx context.Context, bsid net.IP) (domain.SRv6Policy, error) {
+	policies, err := s.ListPolicies(ctx)
+	if err != nil {
+		return domain.SRv6Policy{}, err
+	}
+	for _, policy := range policies {
+		if policy.BSID.Equal(bsid) {
+			return policy, nil
+		}
+	}
+	return domain.SRv6Policy{}, fmt.Errorf("sr policy %v: %w", bsid, ErrNotFound)
+}
+
+// AddSegmentList appends a segment list to the policy and returns the policy
+// with the index VPP assigned to it
+func (s *Service) AddSegmentList(ctx context.Context, bsid net.IP, list domain.SRv6SegmentList) (domain.SRv6Policy, error) {
+	sidList, err := mapper.BuildSrv6SidList(list)
+	if err != nil {
+		return domain.SRv6Policy{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	policy, err := s.GetPolicy(ctx, bsid)
+	if err != nil {
+		return domain.SRv6Policy{}, err
+	}
+	if err := s.addSidList(ctx, bsid, policy.Table, sidList); err != nil {
+		return domain.SRv6Policy{}, err
+	}
+	return s.GetPolicy(ctx, bsid)
+}
+
+func (s *Service) addSidList(ctx context.Context, bsid net.IP, table uint32, sidList sr.Srv6SidList) error {
+	req := &sr.SrPolicyMod{
+		BsidAddr:      ip_types.NewIP6Address(bsid),
+		SrPolicyIndex: ^uint32(0),
+		FibTable:      table,
+		Operation:     sr_types.SR_POLICY_OP_API_ADD,
+		Weight:        sidList.Weight,
+		Sids:          sidList,
+	}
+	_, err := vpp.DoRequest[*sr.SrPolicyMod, *sr.SrPolicyModReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("add segment list to sr policy %v: %w", bsid, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) DeleteSegmentList(ctx context.Context, bsid net.IP, index uint32) error {
+	policy, err := s.GetPolicy(ctx, bsid)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, list := range policy.SegmentLists {
+		if list.Index == index {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("segment list %d of sr policy %v: %w", index, bsid, ErrNotFound)
+	}
+	if len(policy.SegmentLists) == 1 {
+		return ErrLastSegmentList
+	}
+
+	req := &sr.SrPolicyMod{
+		BsidAddr:      ip_types.NewIP6Address(bsid),
+		SrPolicyIndex: ^uint32(0),
+		FibTable:      policy.Table,
+		Operation:     sr_types.SR_POLICY_OP_API_DEL,
+		SlIndex:       index,
+	}
+	_, err = vpp.DoRequest[*sr.SrPolicyMod, *sr.SrPolicyModReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("delete segment list %d of sr policy %v: %w", index, bsid, mapVppError(err))
+	}
+	return nil
+}
+
+// AddSteering steers a prefix into a policy, without a prefix the whole VRF
+// is steered by both default routes
+func (s *Service) AddSteering(ctx context.Context, steering domain.SRv6Steering) error {
+	return s.addDelSteering(ctx, steering, false)
+}
+
+func (s *Service) DeleteSteering(ctx context.Context, steering domain.SRv6Steering) error {
+	return s.addDelSteering(ctx, steering, true)
+}
+
+func (s *Service) addDelSteering(ctx context.Context, steering domain.SRv6Steering, isDel bool) error {
+	if !mapper.IsIPv6(steering.BSID) {
+		return fmt.Errorf("%w: bsid %v is not an IPv6 address", ErrInvalid, steering.BSID)
+	}
+	if !isDel && steering.VRF != 0 && !s.vrfs.VRFExists(steering.VRF) {
+		return fmt.Errorf("steering vrf %d: %w", steering.VRF, ErrVRFNotFound)
+	}
+
+	prefixes := []domain.IPWithPrefix{steering.Prefix}
+	if steering.Prefix.Address == "" {
+		prefixes = defaultSteering
+	}
+	for i, prefix := range prefixes {
+		steering.Prefix = prefix
+		if err := s.steer(ctx, steering, isDel); err != nil {
+			if !isDel {
+				// whole-VRF steering is installed for both families or not at all
+				for _, installed := range prefixes[:i] {
+					steering.Prefix = installed
+					if delErr := s.steer(ctx, steering, true); delErr != nil {
+						err = errors.Join(err, delErr)
+					}
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) steer(ctx context.Context, steering domain.SRv6Steering, isDel bool) error {
+	req, err := mapper.BuildSrSteering(steering, isDel)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	_, err = vpp.DoRequest[*sr.SrSteeringAddDel, *sr.SrSteeringAddDelReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("sr steering %s/%d (is_del=%t): %w",
+			steering.Prefix.Address, steering.Prefix.Prefix, isDel, mapVppError(err))
+	}
+	return nil
+}
+
+func (s *Service) ListSteering(ctx context.Context) ([]domain.SRv6Steering, error) {
+	converter := func(msg api.Message) (domain.SRv6Steering, bool) {
+		details, ok := msg.(*sr.SrSteeringPolDetails)
+		if !ok {
+			return domain.SRv6Steering{}, false
+		}
+		return mapper.ConvertSrSteeringDetails(details)
+	}
+
+	steering, err := vpp.Dump(ctx, s.client, &sr.SrSteeringPolDump{}, converter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump sr steering: %w", err)
+	}
+	return steering, nil
+}
+
+// SetEncapSource sets the outer source address of H.Encaps policies
+func (s *Service) SetEncapSource(ctx context.Context, addr net.IP) error {
+	if !mapper.IsIPv6(addr) {
+		return fmt.Errorf("%w: encap source %v is not an IPv6 address", ErrInvalid, addr)
+	}
+	req := &sr.SrSetEncapSource{EncapsSource: ip_types.NewIP6Address(addr)}
+	_, err := vpp.DoRequest[*sr.SrSetEncapSource, *sr.SrSetEncapSourceReply](s.client, ctx, req)
+	if err != nil {
+		return fmt.Errorf("set sr encap source %v: %w", addr, err)
+	}
+	return nil
+}
+
+func mapVppError(err error) error {
+	if errors.Is(err, api.NO_SUCH_ENTRY) || errors.Is(err, api.NO_SUCH_FIB) || errors.Is(err, api.INVALID_SW_IF_INDEX) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}